@@ -0,0 +1,189 @@
+// Package encoding applies and reverses the content codings that
+// representations are transferred with (RFC 9110, section 8.4.1).
+package encoding
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The supported content codings.
+const (
+	Identity = "identity"
+	Brotli   = "br"
+	Zstd     = "zstd"
+	Gzip     = "gzip"
+	Deflate  = "deflate"
+)
+
+// ErrUnsupportedCoding indicates that a content coding is not supported.
+var ErrUnsupportedCoding = errors.New("encoding: content coding is not supported")
+
+// Codings are the content codings that representations can be compressed
+// with, in order of preference.
+var Codings = []string{Brotli, Zstd, Gzip, Deflate}
+
+var writers = map[string]func(io.Writer) (io.WriteCloser, error){
+	Brotli: func(w io.Writer) (io.WriteCloser, error) { return brotli.NewWriter(w), nil },
+	Zstd:   func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+	Gzip:   func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+
+	// the "deflate" coding is the zlib format (RFC 1950), not raw deflate.
+	Deflate: func(w io.Writer) (io.WriteCloser, error) { return zlib.NewWriter(w), nil },
+}
+
+var readers = map[string]func(io.Reader) (io.ReadCloser, error){
+	Brotli: func(r io.Reader) (io.ReadCloser, error) { return io.NopCloser(brotli.NewReader(r)), nil },
+	Zstd: func(r io.Reader) (io.ReadCloser, error) {
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	},
+	Gzip:     func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	"x-gzip": func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+	Deflate:  zlib.NewReader,
+}
+
+// Supported indicates if content can be decoded from the provided coding.
+func Supported(coding string) bool {
+	coding = strings.ToLower(coding)
+	_, ok := readers[coding]
+	return ok || coding == Identity
+}
+
+// Encode applies the content coding to the provided content.
+func Encode(coding string, b []byte) ([]byte, error) {
+	coding = strings.ToLower(coding)
+	if coding == Identity {
+		return b, nil
+	}
+	writer, ok := writers[coding]
+	if !ok {
+		return nil, ErrUnsupportedCoding
+	}
+	var buf bytes.Buffer
+	w, err := writer(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(b); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// NewReader reverses the content codings, listed in the order that they
+// were applied, of the content read from the provided reader.
+func NewReader(r io.Reader, codings ...string) (io.ReadCloser, error) {
+	decoded := &reader{Reader: r}
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(codings[i])
+		if coding == Identity {
+			continue
+		}
+		constructor, ok := readers[coding]
+		if !ok {
+			decoded.Close()
+			return nil, ErrUnsupportedCoding
+		}
+		rc, err := constructor(decoded.Reader)
+		if err != nil {
+			decoded.Close()
+			return nil, err
+		}
+		decoded.Reader = rc
+		decoded.closers = append(decoded.closers, rc)
+	}
+	return decoded, nil
+}
+
+// reader reads decoded content, closing each of its decoders once done.
+type reader struct {
+	io.Reader
+
+	closers []io.Closer
+}
+
+func (r *reader) Close() (err error) {
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		err = errors.Join(err, r.closers[i].Close())
+	}
+	return
+}
+
+// Preferred determines the content coding, amongst those available, that
+// best suits the provided Accept-Encoding header values (RFC 9110, section
+// 12.5.3). The identity coding is acceptable unless the header excludes it
+// with "identity;q=0", or with "*;q=0" and no entry of its own, and is
+// otherwise preferred unless a coding is acceptable with at least the same
+// quality. Content is therefore only compressed for clients that state the
+// codings they accept, and is left as is when nothing is acceptable.
+func Preferred(accept []string, available ...string) string {
+	qualities := map[string]float64{}
+	for _, value := range accept {
+		for _, element := range strings.Split(value, ",") {
+			coding, q, ok := parseCodingRange(element)
+			if ok {
+				qualities[coding] = q
+			}
+		}
+	}
+	identity, ok := qualities[Identity]
+	if !ok {
+		if identity, ok = qualities["*"]; !ok {
+			identity = 1
+		}
+	}
+	preferred, highest := Identity, identity
+	for _, coding := range Codings {
+		q, ok := qualities[coding]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q == 0 || q < highest || (q == highest && preferred != Identity) {
+			continue
+		}
+		for _, a := range available {
+			if strings.EqualFold(a, coding) {
+				preferred, highest = coding, q
+			}
+		}
+	}
+	return preferred
+}
+
+// parseCodingRange parses a single element of an Accept-Encoding header.
+func parseCodingRange(element string) (string, float64, bool) {
+	parameters := strings.Split(element, ";")
+	coding := strings.ToLower(strings.TrimSpace(parameters[0]))
+	if coding == "" {
+		return "", 0, false
+	}
+	q := 1.0
+	for _, parameter := range parameters[1:] {
+		name, value, found := strings.Cut(strings.TrimSpace(parameter), "=")
+		if !found || strings.ToLower(strings.TrimSpace(name)) != "q" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return "", 0, false
+		}
+		q = parsed
+	}
+	return coding, q, true
+}
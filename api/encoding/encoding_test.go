@@ -0,0 +1,37 @@
+package encoding
+
+import "testing"
+
+func TestPreferred(t *testing.T) {
+	tests := []struct {
+		name      string
+		accept    []string
+		available []string
+		preferred string
+	}{
+		{name: "no header", available: Codings, preferred: Identity},
+		{name: "single coding", accept: []string{"gzip"}, available: Codings, preferred: Gzip},
+		{name: "order of preference", accept: []string{"gzip, br"}, available: Codings, preferred: Brotli},
+		{name: "highest quality", accept: []string{"br;q=0.5, gzip;q=0.8, identity;q=0.1"}, available: Codings, preferred: Gzip},
+		{name: "multiple header values", accept: []string{"deflate;q=0.2, identity;q=0.1", "zstd;q=0.9"}, available: Codings, preferred: Zstd},
+		{name: "unavailable coding", accept: []string{"br"}, available: []string{Gzip}, preferred: Identity},
+		{name: "wildcard", accept: []string{"*"}, available: []string{Gzip}, preferred: Gzip},
+		{name: "excluded coding", accept: []string{"gzip;q=0, *"}, available: []string{Gzip, Deflate}, preferred: Deflate},
+		{name: "identity preferred", accept: []string{"identity, gzip;q=0.5"}, available: Codings, preferred: Identity},
+		{name: "identity by default", accept: []string{"gzip;q=0.5"}, available: Codings, preferred: Identity},
+		{name: "identity by wildcard", accept: []string{"gzip;q=0.5, *;q=0.4"}, available: Codings, preferred: Gzip},
+		{name: "identity tied", accept: []string{"identity;q=0.5, gzip;q=0.5"}, available: Codings, preferred: Gzip},
+		{name: "identity excluded", accept: []string{"identity;q=0, gzip;q=0.1"}, available: Codings, preferred: Gzip},
+		{name: "identity excluded by wildcard", accept: []string{"*;q=0, deflate;q=0.1"}, available: Codings, preferred: Deflate},
+		{name: "identity not excluded by wildcard", accept: []string{"*;q=0, identity;q=0.5"}, available: Codings, preferred: Identity},
+		{name: "nothing acceptable", accept: []string{"identity;q=0"}, available: Codings, preferred: Identity},
+		{name: "malformed quality", accept: []string{"gzip;q=2"}, available: Codings, preferred: Identity},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if preferred := Preferred(test.accept, test.available...); preferred != test.preferred {
+				t.Errorf("expected %s, got %s", test.preferred, preferred)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Package i18n provides the message catalogs that responses are localized
+// with, along with the matching of locales against Accept-Language.
+package i18n
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-yaml/yaml"
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the locale that messages fall back to.
+const DefaultLocale = "en-US"
+
+// ErrMissingMessage indicates that a locale bundle lacks a message that the
+// default locale bundle defines.
+var ErrMissingMessage = errors.New("i18n: locale bundle is missing a message")
+
+//go:embed locales/*.yaml
+var locales embed.FS
+
+// Default is the catalog of the locale bundles shipped with the service.
+var Default = MustLoad(locales, "locales")
+
+// Catalog holds the messages of each supported locale, keyed by message key.
+type Catalog struct {
+	locales  []string
+	messages map[string]map[string]string
+	matcher  language.Matcher
+}
+
+// Load constructs a catalog from the locale bundles within the provided
+// directory. Each bundle is a YAML document named after its locale that
+// associates message keys with messages, and must define every message
+// that the default locale bundle does.
+func Load(fsys fs.FS, dir string) (*Catalog, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{messages: map[string]map[string]string{}}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		messages := map[string]string{}
+		if err := yaml.Unmarshal(b, &messages); err != nil {
+			return nil, fmt.Errorf("i18n: malformed locale bundle %s: %w", entry.Name(), err)
+		}
+		c.messages[strings.TrimSuffix(entry.Name(), ".yaml")] = messages
+	}
+	defaults, ok := c.messages[DefaultLocale]
+	if !ok {
+		return nil, fmt.Errorf("i18n: missing locale bundle for %s", DefaultLocale)
+	}
+
+	// the default locale comes first, so that it is matched when no other
+	// locale is acceptable.
+	c.locales = append(c.locales, DefaultLocale)
+	for locale, messages := range c.messages {
+		for key := range defaults {
+			if _, ok := messages[key]; !ok {
+				return nil, fmt.Errorf("%w: %s lacks %s", ErrMissingMessage, locale, key)
+			}
+		}
+		if locale != DefaultLocale {
+			c.locales = append(c.locales, locale)
+		}
+	}
+	sort.Strings(c.locales[1:])
+	tags := make([]language.Tag, len(c.locales))
+	for i, locale := range c.locales {
+		if tags[i], err = language.Parse(locale); err != nil {
+			return nil, fmt.Errorf("i18n: malformed locale %s: %w", locale, err)
+		}
+	}
+	c.matcher = language.NewMatcher(tags)
+	return c, nil
+}
+
+// MustLoad constructs a catalog like Load, panicking if it cannot.
+func MustLoad(fsys fs.FS, dir string) *Catalog {
+	c, err := Load(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Locales provides the supported locales, starting with the default locale.
+func (c *Catalog) Locales() []string {
+	return append([]string(nil), c.locales...)
+}
+
+// Message provides the message associated with the key in the provided
+// locale, formatted with the provided arguments. Messages missing from the
+// locale fall back to the default locale, and then to the key itself.
+func (c *Catalog) Message(locale, key string, args ...any) string {
+	message, ok := c.messages[locale][key]
+	if !ok {
+		if message, ok = c.messages[DefaultLocale][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Match determines the supported locale that best suits the provided
+// Accept-Language header values, indicating if any of them are acceptable.
+func (c *Catalog) Match(acceptLanguage ...string) (string, bool) {
+	tags, _, err := language.ParseAcceptLanguage(strings.Join(acceptLanguage, ", "))
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale, false
+	}
+	_, index, confidence := c.matcher.Match(tags...)
+	if confidence == language.No {
+		return DefaultLocale, false
+	}
+	return c.locales[index], true
+}
+
+// Error is an error whose explanation can be localized with a catalog.
+type Error struct {
+	key     string
+	message string
+	args    []any
+}
+
+// Errorf constructs an error that is explained by the message associated
+// with the key, formatted with the provided arguments. The provided format
+// explains the error to developers.
+func Errorf(key, format string, args ...any) error {
+	return &Error{key: key, message: fmt.Sprintf(format, args...), args: args}
+}
+
+func (e *Error) Error() string { return e.message }
+
+// Key provides the key of the message that explains the error.
+func (e *Error) Key() string { return e.key }
+
+// Args provides the arguments that the message is formatted with.
+func (e *Error) Args() []any { return e.args }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/freerware/tutor/api/resources"
+	"github.com/freerware/tutor/api/rpc"
 	"github.com/freerware/tutor/api/server"
 
 	"go.uber.org/fx"
@@ -12,20 +13,37 @@ import (
 
 var Module = fx.Options(
 	fx.Provide(resources.NewAccountResource),
+	fx.Provide(resources.NewPostResource),
+	fx.Provide(resources.NewHealthResource),
+	fx.Provide(resources.NewPasswordResource),
+	fx.Provide(resources.NewTokenResource),
+	fx.Provide(resources.NewAuthentication),
+	fx.Provide(server.NewReadiness),
+	fx.Provide(server.NewAccessLog),
+	fx.Provide(server.NewMetrics),
+	fx.Provide(server.NewTracing),
 	fx.Provide(server.New),
+	fx.Provide(rpc.New),
 	fx.Provide(zap.NewDevelopment),
 	fx.Invoke(Start),
 )
 
-func Start(lc fx.Lifecycle, s server.Server) {
+func Start(lc fx.Lifecycle, s server.Server, rs rpc.Server) {
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			go s.Start()
-			return nil
+			return s.Start()
 		},
 		OnStop: func(ctx context.Context) error {
 			return s.Stop(ctx)
 		},
 	})
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return rs.Start()
+		},
+		OnStop: func(ctx context.Context) error {
+			return rs.Stop(ctx)
+		},
+	})
 }
@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification the documents
+// conform to.
+const Version = "3.1.0"
+
+// Operation describes what a handler accepts and responds with.
+type Operation struct {
+	Summary     string
+	Parameters  []Parameter
+	RequestBody *Content
+	Responses   []Response
+
+	// Security names the security schemes that requests to the operation
+	// authenticate with, any one of which suffices.
+	Security []string
+}
+
+// The security schemes that operations can authenticate with.
+const (
+	SecurityBasic  = "basic"
+	SecurityBearer = "bearer"
+)
+
+// securitySchemes describes each of the security schemes.
+var securitySchemes = map[string]securityScheme{
+	SecurityBasic:  {Type: "http", Scheme: "basic"},
+	SecurityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+}
+
+// Parameter describes a query or header parameter of an operation. Path
+// parameters are derived from the route itself.
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Schema      any
+}
+
+// Response describes a response that an operation can produce.
+type Response struct {
+	Status      int
+	Description string
+	Headers     []string
+	Content     *Content
+}
+
+// Content describes a body along with the media types it can be exchanged
+// in. The schema is reflected from the provided value, which is typically a
+// representation.
+type Content struct {
+	MediaTypes []string
+	Schema     any
+}
+
+// Route associates an operation with the method and path it is served at.
+type Route struct {
+	Method    string
+	Path      string
+	Operation Operation
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string                          `json:"openapi" yaml:"openapi"`
+	Info       Info                            `json:"info" yaml:"info"`
+	Paths      map[string]map[string]operation `json:"paths" yaml:"paths"`
+	Components components                      `json:"components" yaml:"components"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema        `json:"schemas" yaml:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+type securityScheme struct {
+	Type         string `json:"type" yaml:"type"`
+	Scheme       string `json:"scheme" yaml:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty" yaml:"bearerFormat,omitempty"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary,omitempty" yaml:"summary,omitempty"`
+	OperationID string                `json:"operationId" yaml:"operationId"`
+	Parameters  []parameter           `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses" yaml:"responses"`
+	Security    []map[string][]string `json:"security,omitempty" yaml:"security,omitempty"`
+}
+
+type parameter struct {
+	Name        string  `json:"name" yaml:"name"`
+	In          string  `json:"in" yaml:"in"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+	Required    bool    `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema      *Schema `json:"schema" yaml:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required" yaml:"required"`
+	Content  map[string]mediaType `json:"content" yaml:"content"`
+}
+
+type response struct {
+	Description string               `json:"description" yaml:"description"`
+	Headers     map[string]header    `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type header struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+// pathParameter matches the variables within a route path.
+var pathParameter = regexp.MustCompile(`{([^}:]+)(:[^}]+)?}`)
+
+// New constructs the document describing the provided routes. Routes that
+// differ only by a trailing slash are described once.
+func New(info Info, routes ...Route) Document {
+	d := Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Paths:      map[string]map[string]operation{},
+		Components: components{Schemas: map[string]*Schema{}},
+	}
+	for _, route := range routes {
+		path := route.Path
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		path = pathParameter.ReplaceAllString(path, "{$1}")
+		method := strings.ToLower(route.Method)
+		if _, ok := d.Paths[path]; !ok {
+			d.Paths[path] = map[string]operation{}
+		}
+		if _, ok := d.Paths[path][method]; ok {
+			continue
+		}
+		d.Paths[path][method] = d.operation(method, path, route.Operation)
+	}
+	return d
+}
+
+func (d *Document) operation(method, path string, o Operation) operation {
+	op := operation{
+		Summary:     o.Summary,
+		OperationID: operationID(method, path),
+		Responses:   map[string]response{},
+	}
+	for _, match := range pathParameter.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	for _, p := range o.Parameters {
+		op.Parameters = append(op.Parameters, parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Schema:      d.schema(p.Schema),
+		})
+	}
+	if o.RequestBody != nil {
+		op.RequestBody = &requestBody{Required: true, Content: d.content(o.RequestBody)}
+	}
+	for _, r := range o.Responses {
+		resp := response{Description: r.Description}
+		if resp.Description == "" {
+			resp.Description = http.StatusText(r.Status)
+		}
+		if len(r.Headers) > 0 {
+			resp.Headers = map[string]header{}
+			for _, h := range r.Headers {
+				resp.Headers[h] = header{Schema: &Schema{Type: "string"}}
+			}
+		}
+		if r.Content != nil {
+			resp.Content = d.content(r.Content)
+		}
+		op.Responses[strconv.Itoa(r.Status)] = resp
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["default"] = response{Description: "Response"}
+	}
+	for _, name := range o.Security {
+		if d.Components.SecuritySchemes == nil {
+			d.Components.SecuritySchemes = map[string]securityScheme{}
+		}
+		d.Components.SecuritySchemes[name] = securitySchemes[name]
+		op.Security = append(op.Security, map[string][]string{name: {}})
+	}
+	return op
+}
+
+func (d *Document) content(c *Content) map[string]mediaType {
+	content := map[string]mediaType{}
+	schema := d.schema(c.Schema)
+	for _, mt := range c.MediaTypes {
+		if isBinary(mt) {
+			content[mt] = mediaType{Schema: &Schema{Type: "string", ContentMediaType: mt}}
+			continue
+		}
+		content[mt] = mediaType{Schema: schema}
+	}
+	return content
+}
+
+// isBinary indicates if the provided media type is a binary format, whose
+// structure is described elsewhere.
+func isBinary(mediaType string) bool {
+	return strings.HasSuffix(mediaType, "protobuf")
+}
+
+// operationID derives a unique identifier for the operation at the path.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(method)
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '.' })
+	for _, segment := range segments {
+		segment = strings.Trim(segment, "{}")
+		if segment == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(segment[:1]) + segment[1:])
+	}
+	return b.String()
+}
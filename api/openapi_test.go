@@ -0,0 +1,458 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/freerware/tutor/api/resources"
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/config"
+	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
+	"github.com/freerware/work/v4/unit"
+	u "github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var (
+	accountUUID = u.Must(u.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	postUUID    = u.Must(u.FromString("6ba7b811-9dad-11d1-80b4-00c04fd430c8"))
+)
+
+// pathParameter matches the variables within a route path, along with the
+// pattern they are constrained to.
+var pathParameter = regexp.MustCompile(`{([^}:]+)(?::([^}]+))?}`)
+
+// documented is the portion of the OpenAPI document that the tests verify.
+type documented struct {
+	Paths map[string]map[string]struct {
+		RequestBody *struct {
+			Content map[string]any `json:"content"`
+		} `json:"requestBody"`
+		Responses map[string]struct {
+			Content map[string]any `json:"content"`
+		} `json:"responses"`
+	} `json:"paths"`
+}
+
+// route is a route registered with the router.
+type route struct {
+	template string
+	method   string
+}
+
+// documentedPath provides the path that the route is described at within
+// the OpenAPI document.
+func (r route) documentedPath() string {
+	path := r.template
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return pathParameter.ReplaceAllString(path, "{$1}")
+}
+
+// paths provides the paths that requests for the route can be made to, one
+// for each of the alternatives of its variables.
+func (r route) paths(t *testing.T) []string {
+	paths := []string{r.template}
+	for _, match := range pathParameter.FindAllStringSubmatch(r.template, -1) {
+		var values []string
+		switch match[1] {
+		case "uuid":
+			values = []string{accountUUID.String()}
+		case "postUUID":
+			values = []string{postUUID.String()}
+		default:
+			if match[2] == "" {
+				t.Fatalf("no value for the %s variable of %s", match[1], r.template)
+			}
+			values = strings.Split(match[2], "|")
+		}
+		expanded := []string{}
+		for _, path := range paths {
+			for _, value := range values {
+				expanded = append(expanded, strings.Replace(path, match[0], value, 1))
+			}
+		}
+		paths = expanded
+	}
+	return paths
+}
+
+// bodies are the request bodies sent to the routes that require one in
+// order to succeed.
+var bodies = map[string]string{
+	"POST /accounts/import": "[]",
+}
+
+// queryer serves a single account, with a single post.
+type queryer struct {
+	account domain.Account
+}
+
+func (q queryer) Query(_ context.Context, uuid u.UUID) infrastructure.AccountQuery {
+	if uuid != q.account.UUID() {
+		return accountQuery{}
+	}
+	return accountQuery{q.account}
+}
+
+func (q queryer) QueryPage(
+	ctx context.Context, offset, limit int) infrastructure.AccountPageQuery {
+	return pageQuery{q.account}
+}
+
+func (q queryer) QueryCursor(
+	filter infrastructure.AccountFilter, posts bool) infrastructure.AccountCursorQuery {
+	return cursorQuery{q.account}
+}
+
+func (q queryer) QueryIdempotencyRecord(
+	key string, now time.Time) infrastructure.IdempotencyRecordQuery {
+	return idempotencyRecordQuery{}
+}
+
+func (q queryer) QueryCredential(
+	ctx context.Context, accountUUID u.UUID) infrastructure.CredentialQuery {
+	return credentialQuery{}
+}
+
+func (q queryer) QueryCredentials(
+	ctx context.Context, username string) infrastructure.CredentialQuery {
+	return credentialQuery{}
+}
+
+func (q queryer) QueryPostAuthor(
+	ctx context.Context, postUUID u.UUID) infrastructure.PostAuthorQuery {
+	return postAuthorQuery{uuid: q.account.UUID()}
+}
+
+type accountQuery []domain.Account
+
+func (q accountQuery) Execute() ([]domain.Account, error) { return q, nil }
+
+type cursorQuery []domain.Account
+
+func (q cursorQuery) Execute(context.Context) (infrastructure.AccountCursor, error) {
+	return &accountCursor{accounts: q, index: -1}, nil
+}
+
+type accountCursor struct {
+	accounts []domain.Account
+	index    int
+}
+
+func (c *accountCursor) Next() bool {
+	c.index++
+	return c.index < len(c.accounts)
+}
+
+func (c *accountCursor) Account() domain.Account { return c.accounts[c.index] }
+func (c *accountCursor) Err() error              { return nil }
+func (c *accountCursor) Close() error            { return nil }
+
+type pageQuery []domain.Account
+
+func (q pageQuery) Execute() ([]domain.Account, int, error) { return q, len(q), nil }
+
+type idempotencyRecordQuery struct{}
+
+func (q idempotencyRecordQuery) Execute() (*infrastructure.IdempotencyRecord, error) {
+	return nil, nil
+}
+
+type credentialQuery struct{}
+
+func (q credentialQuery) Execute() ([]infrastructure.Credential, error) { return nil, nil }
+
+type postAuthorQuery struct {
+	uuid u.UUID
+}
+
+func (q postAuthorQuery) Execute() (*u.UUID, error) { return &q.uuid, nil }
+
+// dataMapper saves nothing.
+type dataMapper struct{}
+
+func (dm dataMapper) Insert(context.Context, unit.MapperContext, ...interface{}) error { return nil }
+func (dm dataMapper) Update(context.Context, unit.MapperContext, ...interface{}) error { return nil }
+func (dm dataMapper) Delete(context.Context, unit.MapperContext, ...interface{}) error { return nil }
+
+// newHandler constructs the handler of the server, whose resources
+// negotiate with the provided strategy. Every request is made on behalf of
+// an admin that authenticated with a password.
+func newHandler(t *testing.T, strategy string) *mux.Router {
+	post := domain.ReconstitutePost(domain.PostParameters{
+		UUID:       postUUID,
+		Title:      "Representational State Transfer",
+		Content:    "Architectural Styles and the Design of Network-based Software Architectures",
+		AuthorUUID: accountUUID,
+		CreatedAt:  time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	})
+	account := domain.ReconstituteAccount(domain.AccountParameters{
+		UUID:      accountUUID,
+		GivenName: "Roy",
+		Surname:   "Fielding",
+		Username:  "fielding",
+		CreatedAt: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	})
+	account.SetPosts([]domain.Post{post})
+
+	c := config.Configuration{}
+	c.Negotiation.Strategy = strategy
+	c.Authentication.TokenKey = strings.Repeat("k", 32)
+	c.Authentication.TokenTTL = 3600
+
+	var s server.Server
+	a := fx.New(
+		fx.NopLogger,
+		fx.Supply(c, zap.NewNop()),
+		fx.Supply(fx.Annotate(queryer{account: account}, fx.As(new(infrastructure.Queryer)))),
+		fx.Supply(fx.Annotate(noop.NewTracerProvider(), fx.As(new(trace.TracerProvider)))),
+		fx.Provide(func() infrastructure.UnitResult {
+			return infrastructure.UnitResult{Option: unit.DataMappers(map[unit.TypeName]unit.DataMapper{
+				unit.TypeNameOf(domain.Account{}):                   dataMapper{},
+				unit.TypeNameOf(infrastructure.IdempotencyRecord{}): dataMapper{},
+				unit.TypeNameOf(infrastructure.Credential{}):        dataMapper{},
+			})}
+		}),
+		fx.Provide(func() server.MiddlewareResult {
+			principal := app.Principal{
+				AccountUUID:     accountUUID,
+				Username:        account.Username(),
+				AuthenticatedBy: app.AuthenticatedWithPassword,
+				Role:            app.RoleAdmin,
+			}
+			return server.MiddlewareResult{Middleware: server.Middleware{
+				Name: "principal",
+				Wrap: func(next http.Handler) http.Handler {
+					return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+						ctx := app.WithPrincipal(request.Context(), principal)
+						next.ServeHTTP(w, request.WithContext(ctx))
+					})
+				},
+			}}
+		}),
+		fx.Provide(infrastructure.NewUniter),
+		app.Module,
+		fx.Provide(resources.NewAccountResource),
+		fx.Provide(resources.NewPostResource),
+		fx.Provide(resources.NewHealthResource),
+		fx.Provide(resources.NewPasswordResource),
+		fx.Provide(resources.NewTokenResource),
+		fx.Provide(server.NewReadiness),
+		fx.Provide(server.New),
+		fx.Populate(&s),
+	)
+	if err := a.Err(); err != nil {
+		t.Fatalf("failed to construct the server: %v", err)
+	}
+	router, ok := s.Handler().(*mux.Router)
+	if !ok {
+		t.Fatalf("expected the handler to be a router, got %T", s.Handler())
+	}
+	return router
+}
+
+// routes provides every route registered with the router, for each of its
+// methods.
+func routes(t *testing.T, router *mux.Router) []route {
+	routes := []route{}
+	err := router.Walk(func(r *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		// the path prefixes of subrouters match no methods of their own.
+		methods, err := r.GetMethods()
+		if err != nil {
+			return nil
+		}
+		template, err := r.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		for _, method := range methods {
+			routes = append(routes, route{template: template, method: method})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk the router: %v", err)
+	}
+	if len(routes) == 0 {
+		t.Fatal("expected routes to be registered")
+	}
+	return routes
+}
+
+// document retrieves the OpenAPI document served by the router.
+func document(t *testing.T, router *mux.Router) documented {
+	response := serve(router, http.MethodGet, "/openapi.json", nil)
+	if response.Code != http.StatusOK {
+		t.Fatalf("failed to retrieve the OpenAPI document: %d", response.Code)
+	}
+	var d documented
+	if err := json.Unmarshal(response.Body.Bytes(), &d); err != nil {
+		t.Fatalf("failed to parse the OpenAPI document: %v", err)
+	}
+	return d
+}
+
+// serve responds to a request made to the router.
+func serve(
+	router *mux.Router, method, path string, header http.Header) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	if body, ok := bodies[method+" "+path]; ok {
+		request = httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+// mediaType provides the media type of the provided content type, without
+// its parameters.
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// sorted provides the keys of the provided set in a stable order.
+func sorted[T any](set map[string]T) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	router := newHandler(t, "")
+	d := document(t, router)
+	for _, r := range routes(t, router) {
+		if _, ok := d.Paths[r.documentedPath()][strings.ToLower(r.method)]; !ok {
+			t.Errorf("%s %s is not documented", r.method, r.template)
+		}
+	}
+}
+
+func TestRoutesServeTheDocumentedMediaTypes(t *testing.T) {
+	routers := []*mux.Router{newHandler(t, "proactive"), newHandler(t, "reactive")}
+	d := document(t, routers[0])
+
+	// every media type within the document, along with those that clients
+	// commonly mistake for them, is requested.
+	candidates := map[string]bool{
+		"application/x-protobuf": true,
+		"text/yaml":              true,
+		"text/plain":             true,
+	}
+	for _, operations := range d.Paths {
+		for _, o := range operations {
+			for _, response := range o.Responses {
+				for mediaType := range response.Content {
+					candidates[mediaType] = true
+				}
+			}
+		}
+	}
+
+	for _, r := range routes(t, routers[0]) {
+		o := d.Paths[r.documentedPath()][strings.ToLower(r.method)]
+		expected := map[int]map[string]bool{}
+		for code, response := range o.Responses {
+			status, err := strconv.Atoi(code)
+			if err != nil || status >= http.StatusBadRequest || len(response.Content) == 0 {
+				continue
+			}
+			expected[status] = map[string]bool{}
+			for mediaType := range response.Content {
+				expected[status][mediaType] = true
+			}
+		}
+		if len(expected) == 0 {
+			continue
+		}
+
+		// the media types served by either strategy are those documented.
+		served := map[int]map[string]bool{}
+		for _, router := range routers {
+			for _, path := range r.paths(t) {
+				for candidate := range candidates {
+					header := http.Header{"Accept": []string{candidate}}
+					response := serve(router, r.method, path, header)
+					if response.Code >= http.StatusBadRequest {
+						continue
+					}
+					if mediaType(response.Header().Get("Content-Type")) != candidate {
+						continue
+					}
+					if served[response.Code] == nil {
+						served[response.Code] = map[string]bool{}
+					}
+					served[response.Code][candidate] = true
+				}
+			}
+		}
+		for status := range expected {
+			e, s := sorted(expected[status]), sorted(served[status])
+			if fmt.Sprint(e) != fmt.Sprint(s) {
+				t.Errorf("%s %s documents %v for %d, but serves %v", r.method, r.template, e, status, s)
+			}
+		}
+		for status := range served {
+			if _, ok := expected[status]; !ok {
+				t.Errorf("%s %s serves %v for the undocumented %d",
+					r.method, r.template, sorted(served[status]), status)
+			}
+		}
+	}
+}
+
+func TestRoutesAcceptTheDocumentedRequestBodies(t *testing.T) {
+	router := newHandler(t, "")
+	d := document(t, router)
+	for _, r := range routes(t, router) {
+		o := d.Paths[r.documentedPath()][strings.ToLower(r.method)]
+		if o.RequestBody == nil {
+			continue
+		}
+		request := httptest.NewRequest(r.method, r.paths(t)[0], strings.NewReader("{}"))
+		request.Header.Set("Content-Type", "application/x-unsupported")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		if response.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("%s %s responded %d to an unsupported request body", r.method, r.template, response.Code)
+			continue
+		}
+		accepted := response.Header().Get("Accept")
+		if r.method == http.MethodPatch {
+			accepted = response.Header().Get("Accept-Patch")
+		}
+		a := strings.Split(accepted, ", ")
+		sort.Strings(a)
+		if e := sorted(o.RequestBody.Content); fmt.Sprint(e) != fmt.Sprint(a) {
+			t.Errorf("%s %s documents %v, but accepts %v", r.method, r.template, e, a)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Media types of the supported patch documents.
+const (
+	MediaTypeMergePatch = "application/merge-patch+json"
+	MediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+// Errors that are potentially thrown while applying patch documents.
+var (
+	ErrUnsupportedMediaType = errors.New("patch: unsupported patch document media type")
+	ErrMalformedPatch       = errors.New("patch: malformed patch document")
+	ErrMalformedDocument    = errors.New("patch: malformed target document")
+	ErrPathNotFound         = errors.New("patch: path does not exist within the document")
+	ErrTestFailed           = errors.New("patch: test operation failed")
+)
+
+// MediaTypes provides the media types of the supported patch documents.
+func MediaTypes() []string {
+	return []string{MediaTypeMergePatch, MediaTypeJSONPatch}
+}
+
+// Apply applies the patch document of the provided media type to the
+// provided JSON document.
+func Apply(mediaType string, document, patch []byte) ([]byte, error) {
+	switch mediaType {
+	case MediaTypeMergePatch:
+		return MergePatch(document, patch)
+	case MediaTypeJSONPatch:
+		return JSONPatch(document, patch)
+	default:
+		return nil, ErrUnsupportedMediaType
+	}
+}
+
+// MergePatch applies a JSON merge patch (RFC 7396) to the provided JSON
+// document.
+func MergePatch(document, patch []byte) ([]byte, error) {
+	var target, p any
+	if err := json.Unmarshal(document, &target); err != nil {
+		return nil, ErrMalformedDocument
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, ErrMalformedPatch
+	}
+	return json.Marshal(merge(target, p))
+}
+
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+		t[name] = merge(t[name], value)
+	}
+	return t
+}
+
+// operation represents a single JSON patch operation.
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// JSONPatch applies a JSON patch (RFC 6902) to the provided JSON document.
+// The operations are applied in order, and the patch is rejected entirely
+// if any one of them fails.
+func JSONPatch(document, patch []byte) ([]byte, error) {
+	var doc any
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, ErrMalformedDocument
+	}
+	var operations []operation
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		return nil, ErrMalformedPatch
+	}
+	for i, op := range operations {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op.Op, err)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+func (op operation) apply(doc any) (any, error) {
+	if op.Path == nil {
+		return nil, ErrMalformedPatch
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, ErrMalformedPatch
+		}
+		var value any
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return nil, ErrMalformedPatch
+		}
+		switch op.Op {
+		case "add":
+			return add(doc, path, value)
+		case "replace":
+			return replace(doc, path, value)
+		default:
+			current, err := get(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrTestFailed
+			}
+			return doc, nil
+		}
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		if op.From == nil {
+			return nil, ErrMalformedPatch
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			return add(doc, path, clone(value))
+		}
+		if isProperPrefix(from, path) {
+			return nil, ErrMalformedPatch
+		}
+		if doc, err = remove(doc, from); err != nil {
+			return nil, err
+		}
+		return add(doc, path, value)
+	default:
+		return nil, ErrMalformedPatch
+	}
+}
+
+// parsePointer parses the provided JSON pointer (RFC 6901) into its
+// reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, ErrMalformedPatch
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// isProperPrefix indicates if the first path is a proper prefix of the
+// second path.
+func isProperPrefix(prefix, path []string) bool {
+	if len(prefix) >= len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// index interprets the provided reference token as an index into an array
+// of the provided length. The upper bound is inclusive when appending.
+func index(token string, length int, appending bool) (int, error) {
+	if appending && token == "-" {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPathNotFound
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > length || (!appending && i == length) {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
+
+// get retrieves the value located at the provided path.
+func get(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]any:
+			value, ok := container[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			doc = value
+		case []any:
+			i, err := index(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return doc, nil
+}
+
+// modify locates the container holding the final token of the provided
+// path, and replaces it with the result of the provided function.
+func modify(
+	doc any, path []string, fn func(container any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	switch container := doc.(type) {
+	case map[string]any:
+		child, ok := container[path[0]]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		modified, err := modify(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[path[0]] = modified
+		return container, nil
+	case []any:
+		i, err := index(path[0], len(container), false)
+		if err != nil {
+			return nil, err
+		}
+		modified, err := modify(container[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[i] = modified
+		return container, nil
+	default:
+		return nil, ErrPathNotFound
+	}
+}
+
+// add adds the value at the provided path.
+func add(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modify(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[token] = value
+			return c, nil
+		case []any:
+			i, err := index(token, len(c), true)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// remove removes the value at the provided path.
+func remove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, ErrMalformedPatch
+	}
+	return modify(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[token]; !ok {
+				return nil, ErrPathNotFound
+			}
+			delete(c, token)
+			return c, nil
+		case []any:
+			i, err := index(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(c[:i], c[i+1:]...), nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// replace replaces the value at the provided path.
+func replace(doc any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modify(doc, path, func(container any, token string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[token]; !ok {
+				return nil, ErrPathNotFound
+			}
+			c[token] = value
+			return c, nil
+		case []any:
+			i, err := index(token, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			c[i] = value
+			return c, nil
+		default:
+			return nil, ErrPathNotFound
+		}
+	})
+}
+
+// clone produces a deep copy of the provided value.
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for name, member := range v {
+			c[name] = clone(member)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, element := range v {
+			c[i] = clone(element)
+		}
+		return c
+	default:
+		return v
+	}
+}
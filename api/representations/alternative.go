@@ -0,0 +1,31 @@
+package representations
+
+import "github.com/freerware/negotiator/representation"
+
+// Alternative describes one of the representations that a negotiable
+// resource is available in.
+type Alternative struct {
+	Location        string
+	ContentType     string
+	ContentLanguage string
+	ContentCharset  string
+	ContentEncoding []string
+	SourceQuality   float32
+}
+
+// NewAlternatives describes each of the provided representations.
+func NewAlternatives(reps ...representation.Representation) []Alternative {
+	alternatives := make([]Alternative, len(reps))
+	for i, rep := range reps {
+		location := rep.ContentLocation()
+		alternatives[i] = Alternative{
+			Location:        location.String(),
+			ContentType:     rep.ContentType(),
+			ContentLanguage: rep.ContentLanguage(),
+			ContentCharset:  rep.ContentCharset(),
+			ContentEncoding: rep.ContentEncoding(),
+			SourceQuality:   rep.SourceQuality(),
+		}
+	}
+	return alternatives
+}
@@ -0,0 +1,151 @@
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/domain"
+)
+
+const mediaTypeCSV = "text/csv"
+
+// The columns of the collection, preceded by a header row naming them.
+var (
+	AccountColumns = []string{
+		"uuid",
+		"primaryCredential",
+		"givenName",
+		"surname",
+		"createdAt",
+		"updatedAt",
+		"deletedAt",
+	}
+	PostColumns = []string{
+		"postUUID",
+		"postTitle",
+		"postContent",
+		"postIsDraft",
+		"postLikes",
+		"postCreatedAt",
+		"postUpdatedAt",
+		"postDeletedAt",
+	}
+)
+
+// Accounts is a collection of accounts streamed as comma separated values
+// (RFC 4180). When posts are requested, each account spans one row for
+// each of its posts, or a single row with empty post columns if it has none.
+type Accounts struct {
+	r.Representation
+
+	posts bool
+}
+
+// Bytes provides the representation of an empty collection as bytes.
+func (a Accounts) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	err := a.Encoder(&b).Flush()
+	return b.Bytes(), err
+}
+
+// FromBytes constructs the representation from bytes.
+func (a Accounts) FromBytes(b []byte) error {
+	return r.ErrStreamed
+}
+
+// Encoder constructs the encoder that writes the collection to the provided
+// writer.
+func (a Accounts) Encoder(w io.Writer) r.AccountEncoder {
+	return &encoder{writer: csv.NewWriter(w), posts: a.posts}
+}
+
+// NewAccounts constructs a new streamed account collection representation.
+func NewAccounts(posts bool) Accounts {
+	collection := Accounts{posts: posts}
+	collection.SetContentCharset("utf-8")
+	collection.SetContentLanguage(i18n.DefaultLocale)
+	collection.SetContentType(mediaTypeCSV)
+	collection.SetSourceQuality(1.0)
+	collection.SetContentEncoding([]string{"identity"})
+	return collection
+}
+
+type encoder struct {
+	writer *csv.Writer
+	posts  bool
+	header bool
+}
+
+func (e *encoder) Encode(a domain.Account) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	account := []string{
+		a.UUID().String(),
+		a.Username(),
+		a.GivenName(),
+		a.Surname(),
+		timestamp(a.CreatedAt()),
+		timestamp(a.UpdatedAt()),
+		optionalTimestamp(a.DeletedAt()),
+	}
+	if !e.posts {
+		return e.writer.Write(account)
+	}
+	if len(a.Posts()) == 0 {
+		return e.writer.Write(append(account, make([]string, len(PostColumns))...))
+	}
+	for _, p := range a.Posts() {
+		post := []string{
+			p.UUID().String(),
+			p.Title(),
+			p.Content(),
+			strconv.FormatBool(p.IsDraft()),
+			strconv.Itoa(p.Likes()),
+			timestamp(p.CreatedAt()),
+			timestamp(p.UpdatedAt()),
+			optionalTimestamp(p.DeletedAt()),
+		}
+		if err := e.writer.Write(append(append([]string{}, account...), post...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// writeHeader writes the header row, unless it has already been written.
+func (e *encoder) writeHeader() error {
+	if e.header {
+		return nil
+	}
+	e.header = true
+	columns := AccountColumns
+	if e.posts {
+		columns = append(append([]string{}, AccountColumns...), PostColumns...)
+	}
+	return e.writer.Write(columns)
+}
+
+func timestamp(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func optionalTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return timestamp(*t)
+}
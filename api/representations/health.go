@@ -0,0 +1,24 @@
+package representations
+
+// The statuses of the service and of its components, as described by the
+// health check response format for HTTP APIs.
+const (
+	HealthPass = "pass"
+	HealthWarn = "warn"
+	HealthFail = "fail"
+)
+
+// Health describes the status of the service, along with the status of each
+// component it depends on.
+type Health struct {
+	Status string
+	Checks []HealthCheck
+}
+
+// HealthCheck describes the status of a single component, along with the
+// reason it is not passing.
+type HealthCheck struct {
+	Component string
+	Status    string
+	Output    string
+}
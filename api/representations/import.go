@@ -0,0 +1,19 @@
+package representations
+
+// ImportReport describes the outcome of importing each record of a bulk
+// import, in the order the records were provided.
+type ImportReport struct {
+	Created int
+	Skipped int
+	Failed  int
+	Records []ImportRecord
+}
+
+// ImportRecord describes the outcome of importing a single record, along
+// with the reason it was skipped or failed.
+type ImportRecord struct {
+	Index   int
+	UUID    string
+	Outcome string
+	Reason  string
+}
@@ -0,0 +1,60 @@
+package json
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/domain"
+)
+
+type Accounts struct {
+	r.Representation `json:"-"`
+
+	Accounts          []Account `json:"accounts"`
+	PageSize          int       `json:"pageSize"`
+	TotalCount        int       `json:"totalCount"`
+	NextPageToken     string    `json:"nextPageToken,omitempty"`
+	PreviousPageToken string    `json:"previousPageToken,omitempty"`
+	Links             Links     `json:"links"`
+}
+
+type Links struct {
+	Self     string `json:"self"`
+	Next     string `json:"next,omitempty"`
+	Previous string `json:"prev,omitempty"`
+}
+
+// Bytes provides the representation as bytes.
+func (a Accounts) Bytes() ([]byte, error) {
+	return a.Base.Bytes(&a)
+}
+
+// FromBytes constructs the representation from bytes.
+func (a Accounts) FromBytes(b []byte) error {
+	return a.Base.FromBytes(b, &a)
+}
+
+// NewAccounts constructs a new account collection representation.
+func NewAccounts(page r.Page, accounts ...domain.Account) Accounts {
+	representations := make([]Account, len(accounts))
+	for i, account := range accounts {
+		representations[i] = NewAccount(account)
+	}
+	collection := Accounts{
+		Accounts:          representations,
+		PageSize:          page.Size,
+		TotalCount:        page.TotalCount,
+		NextPageToken:     page.NextPageToken,
+		PreviousPageToken: page.PreviousPageToken,
+		Links: Links{
+			Self:     page.SelfLink(),
+			Next:     page.NextLink(),
+			Previous: page.PreviousLink(),
+		},
+	}
+	collection.SetContentCharset("ascii")
+	collection.SetContentLanguage(i18n.DefaultLocale)
+	collection.SetContentType("application/json")
+	collection.SetSourceQuality(1.0)
+	collection.SetContentEncoding([]string{"identity"})
+	return collection
+}
@@ -1,17 +1,46 @@
 package json
 
 import (
+	"encoding/json"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
 	r "github.com/freerware/tutor/api/representations"
 )
 
+const (
+	mediaTypeJSON        = "application/json"
+	mediaTypeProblemJSON = "application/problem+json"
+)
+
 type Error struct {
-	r.Representation
+	r.Representation `json:"-"`
+
+	// Type is a URI reference that identifies the problem type.
+	Type string `json:"type"`
+
+	// Title is a short, human-readable summary of the problem type.
+	Title string `json:"title"`
+
+	// Status is the HTTP status code generated for this occurrence of the
+	// problem.
+	Status int `json:"status"`
 
-	// Message represents a user friendly message.
-	Message string `json:"message"`
+	// Detail is a human-readable explanation specific to this occurrence of
+	// the problem.
+	Detail string `json:"detail,omitempty"`
 
-	// DetailedMessage represents an engineer friendly message.
-	DetailedMessage string `json:"detailedMessage"`
+	// Instance is a URI reference that identifies this occurrence of the
+	// problem.
+	Instance string `json:"instance,omitempty"`
+
+	// Errors describes the problems with individual members of the request.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field  string `json:"field"`
+	Detail string `json:"detail"`
 }
 
 // Bytes provides the representation as bytes.
@@ -23,3 +52,31 @@ func (e Error) Bytes() ([]byte, error) {
 func (e Error) FromBytes(b []byte) error {
 	return e.Base.FromBytes(b, &e)
 }
+
+// NewError constructs a new error representation.
+func NewError(p r.Problem) Error {
+	e := Error{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   p.Status,
+		Detail:   p.Detail,
+		Instance: p.Instance,
+	}
+	for _, fe := range p.Errors {
+		e.Errors = append(e.Errors, FieldError{Field: fe.Field, Detail: fe.Detail})
+	}
+	e.SetContentCharset("utf-8")
+	e.SetContentLanguage(i18n.DefaultLocale)
+	e.SetContentType(mediaTypeProblemJSON)
+	e.SetSourceQuality(1.0)
+	e.SetContentEncoding([]string{"identity"})
+	e.SetMarshallers(map[string]representation.Marshaller{
+		mediaTypeProblemJSON: json.Marshal,
+		mediaTypeJSON:        json.Marshal,
+	})
+	e.SetUnmarshallers(map[string]representation.Unmarshaller{
+		mediaTypeProblemJSON: json.Unmarshal,
+		mediaTypeJSON:        json.Unmarshal,
+	})
+	return e
+}
@@ -0,0 +1,52 @@
+package json
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+)
+
+type ImportReport struct {
+	r.Representation `json:"-"`
+
+	Created int            `json:"created"`
+	Skipped int            `json:"skipped"`
+	Failed  int            `json:"failed"`
+	Records []ImportRecord `json:"records"`
+}
+
+type ImportRecord struct {
+	Index   int    `json:"index"`
+	UUID    string `json:"uuid,omitempty"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Bytes provides the representation as bytes.
+func (i ImportReport) Bytes() ([]byte, error) {
+	return i.Base.Bytes(&i)
+}
+
+// FromBytes constructs the representation from bytes.
+func (i *ImportReport) FromBytes(b []byte) error {
+	return i.Base.FromBytes(b, i)
+}
+
+// NewImportReport constructs a new representation of the provided import
+// report.
+func NewImportReport(report r.ImportReport) ImportReport {
+	i := ImportReport{
+		Created: report.Created,
+		Skipped: report.Skipped,
+		Failed:  report.Failed,
+		Records: make([]ImportRecord, len(report.Records)),
+	}
+	for index, record := range report.Records {
+		i.Records[index] = ImportRecord(record)
+	}
+	i.SetContentCharset("utf-8")
+	i.SetContentLanguage(i18n.DefaultLocale)
+	i.SetContentType("application/json")
+	i.SetSourceQuality(1.0)
+	i.SetContentEncoding([]string{"identity"})
+	return i
+}
@@ -0,0 +1,35 @@
+package json
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+)
+
+// PasswordChange replaces the password that an account authenticates with.
+type PasswordChange struct {
+	r.Representation `json:"-"`
+
+	CurrentPassword string `json:"currentPassword"`
+	Password        string `json:"password"`
+}
+
+// Bytes provides the representation as bytes.
+func (p PasswordChange) Bytes() ([]byte, error) {
+	return p.Base.Bytes(&p)
+}
+
+// FromBytes constructs the representation from bytes.
+func (p *PasswordChange) FromBytes(b []byte) error {
+	return p.Base.FromBytes(b, p)
+}
+
+// NewPasswordChange constructs a new, empty password change representation.
+func NewPasswordChange() PasswordChange {
+	p := PasswordChange{}
+	p.SetContentCharset("utf-8")
+	p.SetContentLanguage(i18n.DefaultLocale)
+	p.SetContentType("application/json")
+	p.SetSourceQuality(1.0)
+	p.SetContentEncoding([]string{"identity"})
+	return p
+}
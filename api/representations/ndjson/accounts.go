@@ -0,0 +1,95 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+)
+
+const mediaTypeNDJSON = "application/x-ndjson"
+
+// Account is a line of the newline delimited JSON collection.
+type Account struct {
+	UUID              u.UUID     `json:"uuid"`
+	PrimaryCredential string     `json:"primaryCredential"`
+	GivenName         string     `json:"givenName"`
+	Surname           string     `json:"surname"`
+	Posts             *[]j.Post  `json:"posts,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+	DeletedAt         *time.Time `json:"deletedAt"`
+}
+
+// Accounts is a collection of accounts streamed as newline delimited JSON,
+// with one account per line. The posts of each account are only included
+// when requested.
+type Accounts struct {
+	r.Representation
+
+	posts bool
+}
+
+// Bytes provides the representation of an empty collection as bytes.
+func (a Accounts) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	err := a.Encoder(&b).Flush()
+	return b.Bytes(), err
+}
+
+// FromBytes constructs the representation from bytes.
+func (a Accounts) FromBytes(b []byte) error {
+	return r.ErrStreamed
+}
+
+// Encoder constructs the encoder that writes the collection to the provided
+// writer.
+func (a Accounts) Encoder(w io.Writer) r.AccountEncoder {
+	buffered := bufio.NewWriter(w)
+	return &encoder{writer: buffered, encoder: json.NewEncoder(buffered), posts: a.posts}
+}
+
+// NewAccounts constructs a new streamed account collection representation.
+func NewAccounts(posts bool) Accounts {
+	collection := Accounts{posts: posts}
+	collection.SetContentCharset("utf-8")
+	collection.SetContentLanguage(i18n.DefaultLocale)
+	collection.SetContentType(mediaTypeNDJSON)
+	collection.SetSourceQuality(1.0)
+	collection.SetContentEncoding([]string{"identity"})
+	return collection
+}
+
+type encoder struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	posts   bool
+}
+
+func (e *encoder) Encode(a domain.Account) error {
+	account := Account{
+		UUID:              a.UUID(),
+		GivenName:         a.GivenName(),
+		Surname:           a.Surname(),
+		PrimaryCredential: a.Username(),
+		CreatedAt:         a.CreatedAt(),
+		UpdatedAt:         a.UpdatedAt(),
+		DeletedAt:         a.DeletedAt(),
+	}
+	if e.posts {
+		posts := j.NewPosts(a.Posts()...)
+		account.Posts = &posts
+	}
+	return e.encoder.Encode(account)
+}
+
+func (e *encoder) Flush() error {
+	return e.writer.Flush()
+}
@@ -0,0 +1,65 @@
+package representations
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// The bounds of the number of items within a page.
+const (
+	DefaultPageSize = 20
+	MaximumPageSize = 100
+)
+
+// ErrInvalidPageToken indicates that a page token is malformed.
+var ErrInvalidPageToken = errors.New("representations: page token is malformed")
+
+// Page describes a single page within a paginated collection.
+type Page struct {
+	Size              int
+	TotalCount        int
+	Self              url.URL
+	Next              *url.URL
+	NextPageToken     string
+	Previous          *url.URL
+	PreviousPageToken string
+}
+
+// SelfLink provides the link to the page itself.
+func (p Page) SelfLink() string { return p.Self.String() }
+
+// NextLink provides the link to the next page, if there is one.
+func (p Page) NextLink() string {
+	if p.Next == nil {
+		return ""
+	}
+	return p.Next.String()
+}
+
+// PreviousLink provides the link to the previous page, if there is one.
+func (p Page) PreviousLink() string {
+	if p.Previous == nil {
+		return ""
+	}
+	return p.Previous.String()
+}
+
+// EncodePageToken produces an opaque token for the provided offset.
+func EncodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// DecodePageToken retrieves the offset from the provided opaque token.
+func DecodePageToken(token string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidPageToken
+	}
+	return offset, nil
+}
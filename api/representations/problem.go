@@ -0,0 +1,18 @@
+package representations
+
+// Problem describes an error encountered while processing a request, as
+// specified by RFC 9457.
+type Problem struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	Errors   []FieldError
+}
+
+// FieldError describes a problem with a particular member of the request.
+type FieldError struct {
+	Field  string
+	Detail string
+}
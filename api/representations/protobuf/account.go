@@ -4,11 +4,11 @@ import (
 	"errors"
 
 	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
 	r "github.com/freerware/tutor/api/representations"
 	"github.com/freerware/tutor/api/representations/protobuf/gen"
 	"github.com/freerware/tutor/domain"
 	"github.com/golang/protobuf/proto"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -16,61 +16,68 @@ const (
 	mediaTypeXProtobuf = "application/x-protobuf"
 )
 
-type Account struct {
-	gen.Account
-	r.Representation
-}
-
-// NewAccount constructs a new account representation.
-func NewAccount(a domain.Account) Account {
-	marshaller := func(in any) ([]byte, error) {
+var (
+	marshaller = func(in any) ([]byte, error) {
 		message, ok := in.(proto.Message)
 		if !ok {
 			return []byte{}, errors.New("must provide Protobuf message to marshal successfully")
 		}
 		return proto.Marshal(message)
 	}
-	unmarshaller := func(b []byte, out any) error {
+	unmarshaller = func(b []byte, out any) error {
 		message, ok := out.(proto.Message)
 		if !ok {
 			return errors.New("must provide Protobuf message to unmarshal successfully")
 		}
 		return proto.Unmarshal(b, message)
 	}
-	acc := Account{}
-	acc.UUID = a.UUID().String()
-	acc.GivenName = a.GivenName()
-	acc.Surname = a.Surname()
-	acc.Username = a.Username()
-	c := timestamppb.Timestamp{Seconds: a.CreatedAt().Unix()}
-	acc.CreatedAt = &c
-	u := timestamppb.Timestamp{Seconds: a.UpdatedAt().Unix()}
-	acc.UpdatedAt = &u
-	var d *timestamppb.Timestamp
-	if a.DeletedAt() != nil {
-		d = &timestamppb.Timestamp{Seconds: a.DeletedAt().Unix()}
+	marshallers = map[string]representation.Marshaller{
+		mediaTypeProtobuf:  marshaller,
+		mediaTypeXProtobuf: marshaller,
+	}
+	unmarshallers = map[string]representation.Unmarshaller{
+		mediaTypeProtobuf:  unmarshaller,
+		mediaTypeXProtobuf: unmarshaller,
 	}
-	acc.DeletedAt = d
+)
+
+type Account struct {
+	*gen.Account
+	r.Representation
+}
+
+// NewAccount constructs a new account representation.
+func NewAccount(a domain.Account) Account {
+	acc := Account{Account: newAccount(a)}
 	acc.SetContentCharset("ascii")
-	acc.SetContentLanguage("en-US")
+	acc.SetContentLanguage(i18n.DefaultLocale)
 	acc.SetContentType(mediaTypeProtobuf)
 	acc.SetSourceQuality(1.0)
 	acc.SetContentEncoding([]string{"identity"})
-	acc.SetMarshallers(map[string]representation.Marshaller{
-		mediaTypeProtobuf:  marshaller,
-		mediaTypeXProtobuf: marshaller,
-	})
-	acc.SetUnmarshallers(map[string]representation.Unmarshaller{
-		mediaTypeProtobuf:  unmarshaller,
-		mediaTypeXProtobuf: unmarshaller,
-	})
+	acc.SetMarshallers(marshallers)
+	acc.SetUnmarshallers(unmarshallers)
 	return acc
 }
 
+func newAccount(a domain.Account) *gen.Account {
+	acc := gen.Account{}
+	acc.UUID = a.UUID().String()
+	acc.GivenName = a.GivenName()
+	acc.Surname = a.Surname()
+	acc.Username = a.Username()
+	acc.CreatedAt = newTimestamp(a.CreatedAt())
+	acc.UpdatedAt = newTimestamp(a.UpdatedAt())
+	acc.DeletedAt = newTimestampPtr(a.DeletedAt())
+	for _, post := range a.Posts() {
+		acc.Posts = append(acc.Posts, newPost(post))
+	}
+	return &acc
+}
+
 func (a Account) Bytes() ([]byte, error) {
-	return a.Base.Bytes(&a)
+	return a.Base.Bytes(a.Account)
 }
 
 func (a Account) FromBytes(b []byte) error {
-	return a.Base.FromBytes(b, &a)
+	return a.Base.FromBytes(b, a.Account)
 }
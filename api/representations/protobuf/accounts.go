@@ -0,0 +1,47 @@
+package protobuf
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+	"github.com/freerware/tutor/domain"
+)
+
+type Accounts struct {
+	*gen.Accounts
+	r.Representation
+}
+
+// NewAccounts constructs a new account collection representation.
+func NewAccounts(page r.Page, accounts ...domain.Account) Accounts {
+	collection := Accounts{Accounts: &gen.Accounts{}}
+	for _, account := range accounts {
+		collection.Accounts.Accounts =
+			append(collection.Accounts.Accounts, newAccount(account))
+	}
+	collection.PageSize = int32(page.Size)
+	collection.TotalCount = int64(page.TotalCount)
+	collection.NextPageToken = page.NextPageToken
+	collection.PreviousPageToken = page.PreviousPageToken
+	collection.Links = &gen.Links{
+		Self:     page.SelfLink(),
+		Next:     page.NextLink(),
+		Previous: page.PreviousLink(),
+	}
+	collection.SetContentCharset("ascii")
+	collection.SetContentLanguage(i18n.DefaultLocale)
+	collection.SetContentType(mediaTypeProtobuf)
+	collection.SetSourceQuality(1.0)
+	collection.SetContentEncoding([]string{"identity"})
+	collection.SetMarshallers(marshallers)
+	collection.SetUnmarshallers(unmarshallers)
+	return collection
+}
+
+func (a Accounts) Bytes() ([]byte, error) {
+	return a.Base.Bytes(a.Accounts)
+}
+
+func (a Accounts) FromBytes(b []byte) error {
+	return a.Base.FromBytes(b, a.Accounts)
+}
@@ -0,0 +1,44 @@
+package protobuf
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+)
+
+type Alternatives struct {
+	*gen.Alternatives
+	r.Representation
+}
+
+// NewAlternatives constructs a new representation listing the provided
+// alternatives.
+func NewAlternatives(alternatives ...r.Alternative) Alternatives {
+	list := Alternatives{Alternatives: &gen.Alternatives{}}
+	for _, alternative := range alternatives {
+		list.Alternatives.Alternatives = append(list.Alternatives.Alternatives, &gen.Alternative{
+			Location:        alternative.Location,
+			ContentType:     alternative.ContentType,
+			ContentLanguage: alternative.ContentLanguage,
+			ContentCharset:  alternative.ContentCharset,
+			ContentEncoding: alternative.ContentEncoding,
+			SourceQuality:   alternative.SourceQuality,
+		})
+	}
+	list.SetContentCharset("ascii")
+	list.SetContentLanguage(i18n.DefaultLocale)
+	list.SetContentType(mediaTypeProtobuf)
+	list.SetSourceQuality(1.0)
+	list.SetContentEncoding([]string{"identity"})
+	list.SetMarshallers(marshallers)
+	list.SetUnmarshallers(unmarshallers)
+	return list
+}
+
+func (a Alternatives) Bytes() ([]byte, error) {
+	return a.Base.Bytes(a.Alternatives)
+}
+
+func (a Alternatives) FromBytes(b []byte) error {
+	return a.Base.FromBytes(b, a.Alternatives)
+}
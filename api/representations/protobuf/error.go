@@ -0,0 +1,42 @@
+package protobuf
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+)
+
+type Error struct {
+	*gen.Error
+	r.Representation
+}
+
+// NewError constructs a new error representation.
+func NewError(p r.Problem) Error {
+	e := Error{Error: &gen.Error{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   int32(p.Status),
+		Detail:   p.Detail,
+		Instance: p.Instance,
+	}}
+	for _, fe := range p.Errors {
+		e.Errors = append(e.Errors, &gen.FieldError{Field: fe.Field, Detail: fe.Detail})
+	}
+	e.SetContentCharset("utf-8")
+	e.SetContentLanguage(i18n.DefaultLocale)
+	e.SetContentType(mediaTypeProtobuf)
+	e.SetSourceQuality(1.0)
+	e.SetContentEncoding([]string{"identity"})
+	e.SetMarshallers(marshallers)
+	e.SetUnmarshallers(unmarshallers)
+	return e
+}
+
+func (e Error) Bytes() ([]byte, error) {
+	return e.Base.Bytes(e.Error)
+}
+
+func (e Error) FromBytes(b []byte) error {
+	return e.Base.FromBytes(b, e.Error)
+}
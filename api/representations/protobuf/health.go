@@ -0,0 +1,40 @@
+package protobuf
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+)
+
+type Health struct {
+	*gen.Health
+	r.Representation
+}
+
+// NewHealth constructs a new representation of the provided health.
+func NewHealth(health r.Health) Health {
+	h := Health{Health: &gen.Health{Status: health.Status}}
+	for _, check := range health.Checks {
+		h.Checks = append(h.Checks, &gen.HealthCheck{
+			Component: check.Component,
+			Status:    check.Status,
+			Output:    check.Output,
+		})
+	}
+	h.SetContentCharset("utf-8")
+	h.SetContentLanguage(i18n.DefaultLocale)
+	h.SetContentType(mediaTypeProtobuf)
+	h.SetSourceQuality(1.0)
+	h.SetContentEncoding([]string{"identity"})
+	h.SetMarshallers(marshallers)
+	h.SetUnmarshallers(unmarshallers)
+	return h
+}
+
+func (h Health) Bytes() ([]byte, error) {
+	return h.Base.Bytes(h.Health)
+}
+
+func (h Health) FromBytes(b []byte) error {
+	return h.Base.FromBytes(b, h.Health)
+}
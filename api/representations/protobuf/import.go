@@ -0,0 +1,46 @@
+package protobuf
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+)
+
+type ImportReport struct {
+	*gen.ImportReport
+	r.Representation
+}
+
+// NewImportReport constructs a new representation of the provided import
+// report.
+func NewImportReport(report r.ImportReport) ImportReport {
+	i := ImportReport{ImportReport: &gen.ImportReport{
+		Created: int32(report.Created),
+		Skipped: int32(report.Skipped),
+		Failed:  int32(report.Failed),
+	}}
+	for _, record := range report.Records {
+		i.Records = append(i.Records, &gen.ImportRecord{
+			Index:   int32(record.Index),
+			UUID:    record.UUID,
+			Outcome: record.Outcome,
+			Reason:  record.Reason,
+		})
+	}
+	i.SetContentCharset("utf-8")
+	i.SetContentLanguage(i18n.DefaultLocale)
+	i.SetContentType(mediaTypeProtobuf)
+	i.SetSourceQuality(1.0)
+	i.SetContentEncoding([]string{"identity"})
+	i.SetMarshallers(marshallers)
+	i.SetUnmarshallers(unmarshallers)
+	return i
+}
+
+func (i ImportReport) Bytes() ([]byte, error) {
+	return i.Base.Bytes(i.ImportReport)
+}
+
+func (i ImportReport) FromBytes(b []byte) error {
+	return i.Base.FromBytes(b, i.ImportReport)
+}
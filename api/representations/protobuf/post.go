@@ -0,0 +1,48 @@
+package protobuf
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+	"github.com/freerware/tutor/domain"
+)
+
+type Post struct {
+	*gen.Post
+	r.Representation
+}
+
+// NewPost constructs a new post representation.
+func NewPost(p domain.Post) Post {
+	post := Post{Post: newPost(p)}
+	post.SetContentCharset("ascii")
+	post.SetContentLanguage(i18n.DefaultLocale)
+	post.SetContentType(mediaTypeProtobuf)
+	post.SetSourceQuality(1.0)
+	post.SetContentEncoding([]string{"identity"})
+	post.SetMarshallers(marshallers)
+	post.SetUnmarshallers(unmarshallers)
+	return post
+}
+
+func newPost(p domain.Post) *gen.Post {
+	post := gen.Post{}
+	post.UUID = p.UUID().String()
+	post.AuthorUUID = p.AuthorUUID().String()
+	post.Title = p.Title()
+	post.Content = p.Content()
+	post.IsDraft = p.IsDraft()
+	post.Likes = int64(p.Likes())
+	post.CreatedAt = newTimestamp(p.CreatedAt())
+	post.UpdatedAt = newTimestamp(p.UpdatedAt())
+	post.DeletedAt = newTimestampPtr(p.DeletedAt())
+	return &post
+}
+
+func (p Post) Bytes() ([]byte, error) {
+	return p.Base.Bytes(p.Post)
+}
+
+func (p Post) FromBytes(b []byte) error {
+	return p.Base.FromBytes(b, p.Post)
+}
@@ -0,0 +1,37 @@
+package protobuf
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+	"github.com/freerware/tutor/domain"
+)
+
+type Posts struct {
+	*gen.Posts
+	r.Representation
+}
+
+// NewPostCollection constructs a new post collection representation.
+func NewPostCollection(posts ...domain.Post) Posts {
+	collection := Posts{Posts: &gen.Posts{}}
+	for _, post := range posts {
+		collection.Posts.Posts = append(collection.Posts.Posts, newPost(post))
+	}
+	collection.SetContentCharset("ascii")
+	collection.SetContentLanguage(i18n.DefaultLocale)
+	collection.SetContentType(mediaTypeProtobuf)
+	collection.SetSourceQuality(1.0)
+	collection.SetContentEncoding([]string{"identity"})
+	collection.SetMarshallers(marshallers)
+	collection.SetUnmarshallers(unmarshallers)
+	return collection
+}
+
+func (p Posts) Bytes() ([]byte, error) {
+	return p.Base.Bytes(p.Posts)
+}
+
+func (p Posts) FromBytes(b []byte) error {
+	return p.Base.FromBytes(b, p.Posts)
+}
@@ -0,0 +1,41 @@
+package protobuf
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newTimestamp converts the provided time into a timestamp, retaining its
+// nanosecond precision.
+func newTimestamp(t time.Time) *timestamppb.Timestamp {
+	return &timestamppb.Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}
+}
+
+// newTimestampPtr converts the provided time into a timestamp, preserving
+// its absence.
+func newTimestampPtr(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return newTimestamp(*t)
+}
+
+// Time converts the provided timestamp into a time, treating its absence as
+// the zero time.
+func Time(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return time.Unix(t.Seconds, int64(t.Nanos)).UTC()
+}
+
+// TimePtr converts the provided timestamp into a time, preserving its
+// absence.
+func TimePtr(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := Time(t)
+	return &converted
+}
@@ -0,0 +1,31 @@
+package representations
+
+import (
+	"errors"
+	"io"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/domain"
+)
+
+// ErrStreamed indicates that a representation can only be written
+// incrementally, and never read.
+var ErrStreamed = errors.New("representations: streamed representations cannot be read")
+
+// AccountStream is a representation of a collection of accounts that is
+// written one account at a time, rather than all at once.
+type AccountStream interface {
+	representation.Representation
+
+	// Encoder constructs the encoder that writes the collection to the
+	// provided writer.
+	Encoder(io.Writer) AccountEncoder
+}
+
+// AccountEncoder writes the accounts of a streamed collection.
+type AccountEncoder interface {
+	Encode(domain.Account) error
+
+	// Flush writes any buffered accounts.
+	Flush() error
+}
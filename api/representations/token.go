@@ -0,0 +1,12 @@
+package representations
+
+import "time"
+
+// Token describes a bearer token issued to a principal, along with when it
+// expires.
+type Token struct {
+	AccessToken string
+	TokenType   string
+	ExpiresIn   int
+	ExpiresAt   time.Time
+}
@@ -1,17 +1,48 @@
 package xml
 
 import (
+	"encoding/xml"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
 	r "github.com/freerware/tutor/api/representations"
 )
 
+const (
+	mediaTypeXML        = "application/xml"
+	mediaTypeProblemXML = "application/problem+xml"
+)
+
 type Error struct {
-	r.Representation
+	r.Representation `xml:"-"`
+
+	XMLName xml.Name `xml:"urn:ietf:rfc:7807 problem"`
+
+	// Type is a URI reference that identifies the problem type.
+	Type string `xml:"type"`
+
+	// Title is a short, human-readable summary of the problem type.
+	Title string `xml:"title"`
 
-	// Message represents a user friendly message.
-	Message string `xml:"message"`
+	// Status is the HTTP status code generated for this occurrence of the
+	// problem.
+	Status int `xml:"status"`
 
-	// DetailedMessage represents an engineer friendly message.
-	DetailedMessage string `xml:"detailedMessage"`
+	// Detail is a human-readable explanation specific to this occurrence of
+	// the problem.
+	Detail string `xml:"detail,omitempty"`
+
+	// Instance is a URI reference that identifies this occurrence of the
+	// problem.
+	Instance string `xml:"instance,omitempty"`
+
+	// Errors describes the problems with individual members of the request.
+	Errors []FieldError `xml:"error,omitempty"`
+}
+
+type FieldError struct {
+	Field  string `xml:"field"`
+	Detail string `xml:"detail"`
 }
 
 // Bytes provides the representation as bytes.
@@ -23,3 +54,31 @@ func (e Error) Bytes() ([]byte, error) {
 func (e Error) FromBytes(b []byte) error {
 	return e.Base.FromBytes(b, &e)
 }
+
+// NewError constructs a new error representation.
+func NewError(p r.Problem) Error {
+	e := Error{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   p.Status,
+		Detail:   p.Detail,
+		Instance: p.Instance,
+	}
+	for _, fe := range p.Errors {
+		e.Errors = append(e.Errors, FieldError{Field: fe.Field, Detail: fe.Detail})
+	}
+	e.SetContentCharset("utf-8")
+	e.SetContentLanguage(i18n.DefaultLocale)
+	e.SetContentType(mediaTypeProblemXML)
+	e.SetSourceQuality(1.0)
+	e.SetContentEncoding([]string{"identity"})
+	e.SetMarshallers(map[string]representation.Marshaller{
+		mediaTypeProblemXML: xml.Marshal,
+		mediaTypeXML:        xml.Marshal,
+	})
+	e.SetUnmarshallers(map[string]representation.Unmarshaller{
+		mediaTypeProblemXML: xml.Unmarshal,
+		mediaTypeXML:        xml.Unmarshal,
+	})
+	return e
+}
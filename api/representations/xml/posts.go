@@ -0,0 +1,34 @@
+package xml
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/domain"
+)
+
+type Posts struct {
+	r.Representation `xml:"-"`
+
+	Posts []Post `xml:"posts"`
+}
+
+// Bytes provides the representation as bytes.
+func (p Posts) Bytes() ([]byte, error) {
+	return p.Base.Bytes(&p)
+}
+
+// FromBytes constructs the representation from bytes.
+func (p Posts) FromBytes(b []byte) error {
+	return p.Base.FromBytes(b, &p)
+}
+
+// NewPostCollection constructs a new post collection representation.
+func NewPostCollection(posts ...domain.Post) Posts {
+	collection := Posts{Posts: NewPosts(posts...)}
+	collection.SetContentCharset("ascii")
+	collection.SetContentLanguage(i18n.DefaultLocale)
+	collection.SetContentType("application/xml")
+	collection.SetSourceQuality(1.0)
+	collection.SetContentEncoding([]string{"identity"})
+	return collection
+}
@@ -3,6 +3,7 @@ package yaml
 import (
 	"time"
 
+	"github.com/freerware/tutor/api/i18n"
 	r "github.com/freerware/tutor/api/representations"
 	"github.com/freerware/tutor/domain"
 	u "github.com/gofrs/uuid"
@@ -19,6 +20,10 @@ type Account struct {
 	CreatedAt         time.Time  `yaml:"createdAt"`
 	UpdatedAt         time.Time  `yaml:"updatedAt"`
 	DeletedAt         *time.Time `yaml:"deletedAt"`
+
+	// Password is the password that a new account authenticates with, which
+	// is accepted in requests but never included in responses.
+	Password string `yaml:"password,omitempty"`
 }
 
 // Bytes provides the representation as bytes.
@@ -27,8 +32,8 @@ func (a Account) Bytes() ([]byte, error) {
 }
 
 // FromBytes constructs the representation from bytes.
-func (a Account) FromBytes(b []byte) error {
-	return a.Base.FromBytes(b, &a)
+func (a *Account) FromBytes(b []byte) error {
+	return a.Base.FromBytes(b, a)
 }
 
 // NewAccount constructs a new account representation.
@@ -44,7 +49,7 @@ func NewAccount(a domain.Account) Account {
 		DeletedAt:         a.DeletedAt(),
 	}
 	account.SetContentCharset("ascii")
-	account.SetContentLanguage("en-US")
+	account.SetContentLanguage(i18n.DefaultLocale)
 	account.SetContentType("application/yaml")
 	account.SetSourceQuality(1.0)
 	account.SetContentEncoding([]string{"identity"})
@@ -0,0 +1,60 @@
+package yaml
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	"github.com/freerware/tutor/domain"
+)
+
+type Accounts struct {
+	r.Representation `yaml:"-"`
+
+	Accounts          []Account `yaml:"accounts"`
+	PageSize          int       `yaml:"pageSize"`
+	TotalCount        int       `yaml:"totalCount"`
+	NextPageToken     string    `yaml:"nextPageToken,omitempty"`
+	PreviousPageToken string    `yaml:"previousPageToken,omitempty"`
+	Links             Links     `yaml:"links"`
+}
+
+type Links struct {
+	Self     string `yaml:"self"`
+	Next     string `yaml:"next,omitempty"`
+	Previous string `yaml:"prev,omitempty"`
+}
+
+// Bytes provides the representation as bytes.
+func (a Accounts) Bytes() ([]byte, error) {
+	return a.Base.Bytes(&a)
+}
+
+// FromBytes constructs the representation from bytes.
+func (a Accounts) FromBytes(b []byte) error {
+	return a.Base.FromBytes(b, &a)
+}
+
+// NewAccounts constructs a new account collection representation.
+func NewAccounts(page r.Page, accounts ...domain.Account) Accounts {
+	representations := make([]Account, len(accounts))
+	for i, account := range accounts {
+		representations[i] = NewAccount(account)
+	}
+	collection := Accounts{
+		Accounts:          representations,
+		PageSize:          page.Size,
+		TotalCount:        page.TotalCount,
+		NextPageToken:     page.NextPageToken,
+		PreviousPageToken: page.PreviousPageToken,
+		Links: Links{
+			Self:     page.SelfLink(),
+			Next:     page.NextLink(),
+			Previous: page.PreviousLink(),
+		},
+	}
+	collection.SetContentCharset("ascii")
+	collection.SetContentLanguage(i18n.DefaultLocale)
+	collection.SetContentType("application/yaml")
+	collection.SetSourceQuality(1.0)
+	collection.SetContentEncoding([]string{"identity"})
+	return collection
+}
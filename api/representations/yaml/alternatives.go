@@ -0,0 +1,46 @@
+package yaml
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+)
+
+type Alternatives struct {
+	r.Representation `yaml:"-"`
+
+	Alternatives []Alternative `yaml:"alternatives"`
+}
+
+type Alternative struct {
+	Location        string   `yaml:"location"`
+	ContentType     string   `yaml:"contentType"`
+	ContentLanguage string   `yaml:"contentLanguage,omitempty"`
+	ContentCharset  string   `yaml:"contentCharset,omitempty"`
+	ContentEncoding []string `yaml:"contentEncoding,omitempty"`
+	SourceQuality   float32  `yaml:"sourceQuality"`
+}
+
+// Bytes provides the representation as bytes.
+func (a Alternatives) Bytes() ([]byte, error) {
+	return a.Base.Bytes(&a)
+}
+
+// FromBytes constructs the representation from bytes.
+func (a *Alternatives) FromBytes(b []byte) error {
+	return a.Base.FromBytes(b, a)
+}
+
+// NewAlternatives constructs a new representation listing the provided
+// alternatives.
+func NewAlternatives(alternatives ...r.Alternative) Alternatives {
+	list := Alternatives{Alternatives: make([]Alternative, len(alternatives))}
+	for i, alternative := range alternatives {
+		list.Alternatives[i] = Alternative(alternative)
+	}
+	list.SetContentCharset("ascii")
+	list.SetContentLanguage(i18n.DefaultLocale)
+	list.SetContentType("application/yaml")
+	list.SetSourceQuality(1.0)
+	list.SetContentEncoding([]string{"identity"})
+	return list
+}
@@ -1,17 +1,45 @@
 package yaml
 
 import (
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
 	r "github.com/freerware/tutor/api/representations"
+	"github.com/go-yaml/yaml"
+)
+
+const (
+	mediaTypeYAML        = "application/yaml"
+	mediaTypeProblemYAML = "application/problem+yaml"
 )
 
 type Error struct {
-	r.Representation
+	r.Representation `yaml:"-"`
+
+	// Type is a URI reference that identifies the problem type.
+	Type string `yaml:"type"`
+
+	// Title is a short, human-readable summary of the problem type.
+	Title string `yaml:"title"`
+
+	// Status is the HTTP status code generated for this occurrence of the
+	// problem.
+	Status int `yaml:"status"`
 
-	// Message represents a user friendly message.
-	Message string `yaml:"message"`
+	// Detail is a human-readable explanation specific to this occurrence of
+	// the problem.
+	Detail string `yaml:"detail,omitempty"`
 
-	// DetailedMessage represents an engineer friendly message.
-	DetailedMessage string `yaml:"detailedMessage"`
+	// Instance is a URI reference that identifies this occurrence of the
+	// problem.
+	Instance string `yaml:"instance,omitempty"`
+
+	// Errors describes the problems with individual members of the request.
+	Errors []FieldError `yaml:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field  string `yaml:"field"`
+	Detail string `yaml:"detail"`
 }
 
 // Bytes provides the representation as bytes.
@@ -23,3 +51,31 @@ func (e Error) Bytes() ([]byte, error) {
 func (e Error) FromBytes(b []byte) error {
 	return e.Base.FromBytes(b, &e)
 }
+
+// NewError constructs a new error representation.
+func NewError(p r.Problem) Error {
+	e := Error{
+		Type:     p.Type,
+		Title:    p.Title,
+		Status:   p.Status,
+		Detail:   p.Detail,
+		Instance: p.Instance,
+	}
+	for _, fe := range p.Errors {
+		e.Errors = append(e.Errors, FieldError{Field: fe.Field, Detail: fe.Detail})
+	}
+	e.SetContentCharset("utf-8")
+	e.SetContentLanguage(i18n.DefaultLocale)
+	e.SetContentType(mediaTypeProblemYAML)
+	e.SetSourceQuality(1.0)
+	e.SetContentEncoding([]string{"identity"})
+	e.SetMarshallers(map[string]representation.Marshaller{
+		mediaTypeProblemYAML: yaml.Marshal,
+		mediaTypeYAML:        yaml.Marshal,
+	})
+	e.SetUnmarshallers(map[string]representation.Unmarshaller{
+		mediaTypeProblemYAML: yaml.Unmarshal,
+		mediaTypeYAML:        yaml.Unmarshal,
+	})
+	return e
+}
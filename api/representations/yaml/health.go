@@ -0,0 +1,46 @@
+package yaml
+
+import (
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+)
+
+type Health struct {
+	r.Representation `yaml:"-"`
+
+	Status string        `yaml:"status"`
+	Checks []HealthCheck `yaml:"checks"`
+}
+
+type HealthCheck struct {
+	Component string `yaml:"component"`
+	Status    string `yaml:"status"`
+	Output    string `yaml:"output,omitempty"`
+}
+
+// Bytes provides the representation as bytes.
+func (h Health) Bytes() ([]byte, error) {
+	return h.Base.Bytes(&h)
+}
+
+// FromBytes constructs the representation from bytes.
+func (h *Health) FromBytes(b []byte) error {
+	return h.Base.FromBytes(b, h)
+}
+
+// NewHealth constructs a new representation of the provided health.
+func NewHealth(health r.Health) Health {
+	h := Health{
+		Status: health.Status,
+		Checks: make([]HealthCheck, len(health.Checks)),
+	}
+	for index, check := range health.Checks {
+		h.Checks[index] = HealthCheck(check)
+	}
+	h.SetContentCharset("utf-8")
+	h.SetContentLanguage(i18n.DefaultLocale)
+	h.SetContentType("application/yaml")
+	h.SetSourceQuality(1.0)
+	h.SetContentEncoding([]string{"identity"})
+	return h
+}
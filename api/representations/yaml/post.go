@@ -3,6 +3,7 @@ package yaml
 import (
 	"time"
 
+	"github.com/freerware/tutor/api/i18n"
 	r "github.com/freerware/tutor/api/representations"
 	"github.com/freerware/tutor/domain"
 	u "github.com/gofrs/uuid"
@@ -27,8 +28,8 @@ func (p Post) Bytes() ([]byte, error) {
 }
 
 // FromBytes constructs the representation from bytes.
-func (p Post) FromBytes(b []byte) error {
-	return p.Base.FromBytes(b, &p)
+func (p *Post) FromBytes(b []byte) error {
+	return p.Base.FromBytes(b, p)
 }
 
 // NewPost constructs a new account representation.
@@ -44,7 +45,7 @@ func NewPost(p domain.Post) Post {
 		DeletedAt: p.DeletedAt(),
 	}
 	post.SetContentCharset("ascii")
-	post.SetContentLanguage("en-US")
+	post.SetContentLanguage(i18n.DefaultLocale)
 	post.SetContentType("application/yaml")
 	post.SetSourceQuality(1.0)
 	post.SetContentEncoding([]string{"identity"})
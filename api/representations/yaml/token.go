@@ -0,0 +1,43 @@
+package yaml
+
+import (
+	"time"
+
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+)
+
+type Token struct {
+	r.Representation `yaml:"-"`
+
+	AccessToken string    `yaml:"accessToken"`
+	TokenType   string    `yaml:"tokenType"`
+	ExpiresIn   int       `yaml:"expiresIn"`
+	ExpiresAt   time.Time `yaml:"expiresAt"`
+}
+
+// Bytes provides the representation as bytes.
+func (t Token) Bytes() ([]byte, error) {
+	return t.Base.Bytes(&t)
+}
+
+// FromBytes constructs the representation from bytes.
+func (t *Token) FromBytes(b []byte) error {
+	return t.Base.FromBytes(b, t)
+}
+
+// NewToken constructs a new representation of the provided token.
+func NewToken(token r.Token) Token {
+	t := Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		ExpiresIn:   token.ExpiresIn,
+		ExpiresAt:   token.ExpiresAt,
+	}
+	t.SetContentCharset("utf-8")
+	t.SetContentLanguage(i18n.DefaultLocale)
+	t.SetContentType("application/yaml")
+	t.SetSourceQuality(1.0)
+	t.SetContentEncoding([]string{"identity"})
+	return t
+}
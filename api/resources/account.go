@@ -1,21 +1,24 @@
 package resources
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
-	"github.com/freerware/negotiator"
-	"github.com/freerware/negotiator/proactive"
 	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
 	j "github.com/freerware/tutor/api/representations/json"
 	p "github.com/freerware/tutor/api/representations/protobuf"
 	x "github.com/freerware/tutor/api/representations/xml"
 	y "github.com/freerware/tutor/api/representations/yaml"
 	"github.com/freerware/tutor/api/server"
 	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/config"
 	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
 	u "github.com/gofrs/uuid"
 	"github.com/gorilla/mux"
 	"go.uber.org/fx"
@@ -26,32 +29,46 @@ type AccountResourceResult struct {
 	fx.Out
 
 	AccountResource  AccountResource
-	MuxConfiguration server.MuxConfiguration
+	MuxConfiguration server.MuxConfiguration `group:"muxConfigurations"`
 }
 
 type AccountResourceParameters struct {
 	fx.In
 
-	AccountService app.AccountService
-	Logger         *zap.Logger
+	AccountService     app.AccountService
+	IdempotencyService app.IdempotencyService
+	Configuration      config.Configuration
+	Logger             *zap.Logger
 }
 
 type AccountResource struct {
 	accountService app.AccountService
+	negotiation    negotiation
+	compression    compression
+	idempotency    idempotency
+	batchSize      int
 	logger         *zap.Logger
 }
 
 func NewAccountResource(
 	parameters AccountResourceParameters,
-) AccountResourceResult {
+) (AccountResourceResult, error) {
+	n, err := newNegotiation(parameters.Configuration)
+	if err != nil {
+		return AccountResourceResult{}, err
+	}
 	a := AccountResource{
 		accountService: parameters.AccountService,
+		negotiation:    n,
+		compression:    newCompression(parameters.Configuration.Compression),
+		idempotency:    newIdempotency(parameters.IdempotencyService, parameters.Configuration, parameters.Logger),
+		batchSize:      parameters.Configuration.Import.BatchSize,
 		logger:         parameters.Logger,
 	}
 	return AccountResourceResult{
 		AccountResource:  a,
 		MuxConfiguration: a.MuxConfiguration(),
-	}
+	}, nil
 }
 
 func (ar *AccountResource) Get(w http.ResponseWriter, request *http.Request) {
@@ -60,43 +77,289 @@ func (ar *AccountResource) Get(w http.ResponseWriter, request *http.Request) {
 	vars := mux.Vars(request)
 	uuid, err := u.FromString(vars["uuid"])
 	if err != nil {
-		http.Error(w, err.Error(), 400)
+		writeError(w, request, ar.logger, newProblem(problemTypeMalformedUUID, err))
+		return
 	}
 
 	// retrieve the account.
-	account, err := ar.accountService.Get(uuid)
+	account, err := ar.accountService.Get(request.Context(), uuid)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
+	representations, err := ar.representations(account, resourceLocation(request))
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+
+	// evaluate the preconditions against the representation to be served.
+	selected, err := ar.negotiation.selected(request, representations...)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+	if selected != nil {
+		writeValidators(w, selected)
+	}
+	if status := preconditions(request, selected, representations...); status != 0 {
+		writePreconditionStatus(w, request, ar.logger, status)
+		return
+	}
+
+	// negotiate.
+	if err = ar.negotiation.negotiate(w, request, representations...); err != nil {
+		writeError(w, request, ar.logger, err)
+	}
+}
+
+// representations constructs the available representations of the account,
+// along with their validators.
+func (ar *AccountResource) representations(
+	account domain.Account, location url.URL) ([]representation.Representation, error) {
+	reps, err := ar.compression.compress(accountRepresentations(account, location)...)
+	if err != nil {
+		return nil, err
+	}
+	return validate(accountLastModified(account), reps...)
+}
+
+// accountRepresentations constructs the uncompressed representations of the
+// account, each located at the variant resource that serves it.
+func accountRepresentations(
+	account domain.Account, location url.URL) []representation.Representation {
 	jacc := j.NewAccount(account)
-	jacc.SetContentLocation(*request.URL)
-	gjacc := j.NewAccount(account)
-	gjacc.SetContentLocation(*request.URL)
-	gjacc.SetContentEncoding([]string{"gzip"})
 	yacc := y.NewAccount(account)
-	yacc.SetContentLocation(*request.URL)
 	xacc := x.NewAccount(account)
-	xacc.SetContentLocation(*request.URL)
 	pacc := p.NewAccount(account)
-	pacc.SetContentLocation(*request.URL)
-	representations := []representation.Representation{jacc, yacc, xacc, gjacc, pacc}
+	return located(location, &jacc, &yacc, &xacc, &pacc)
+}
+
+// precondition constructs the precondition described by the conditional
+// headers of an unsafe request, which is evaluated against the current state
+// of the account, if it exists.
+func (ar *AccountResource) precondition(request *http.Request) app.Precondition {
+	return func(account *domain.Account) error {
+		var current []representation.Representation
+		if account != nil {
+			var err error
+			if current, err = ar.representations(*account, resourceLocation(request)); err != nil {
+				return err
+			}
+		}
+		if status := preconditions(request, nil, current...); status != 0 {
+			return newProblem(problemTypePreconditionFailed, errPreconditionFailed)
+		}
+		return nil
+	}
+}
+
+// accountLastModified determines when the account, or any of its posts,
+// was last modified.
+func accountLastModified(account domain.Account) time.Time {
+	lastModified := account.UpdatedAt()
+	for _, post := range account.Posts() {
+		if post.UpdatedAt().After(lastModified) {
+			lastModified = post.UpdatedAt()
+		}
+	}
+	return lastModified
+}
+
+func (ar *AccountResource) List(w http.ResponseWriter, request *http.Request) {
+
+	// determine the requested page.
+	pr, err := newPageRequest(request.URL)
+	if err != nil {
+		writeError(w, request, ar.logger, newProblem(problemTypeInvalidPage, err))
+		return
+	}
+
+	// retrieve the accounts.
+	accounts, total, err := ar.accountService.List(request.Context(), pr.offset, pr.size)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+
+	location := resourceLocation(request)
+	page := pr.page(location, len(accounts), total)
+	representations, err := ar.compression.compress(
+		accountsRepresentations(page, location, accounts...)...)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+
+	// advertise the neighbouring pages.
+	if page.Next != nil {
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"next\"", page.NextLink()))
+	}
+	if page.Previous != nil {
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"prev\"", page.PreviousLink()))
+	}
 
 	// negotiate.
-	ctx := negotiator.NegotiationContext{Request: request, ResponseWriter: w}
-	if err = proactive.Default.Negotiate(ctx, representations...); err != nil {
-		http.Error(w, err.Error(), 500)
+	if err = ar.negotiation.negotiate(w, request, representations...); err != nil {
+		writeError(w, request, ar.logger, err)
+	}
+}
+
+// accountsRepresentations constructs the uncompressed representations of
+// the page of accounts, each located at the variant resource that serves it.
+func accountsRepresentations(
+	page r.Page, location url.URL, accounts ...domain.Account) []representation.Representation {
+	jaccs := j.NewAccounts(page, accounts...)
+	yaccs := y.NewAccounts(page, accounts...)
+	xaccs := x.NewAccounts(page, accounts...)
+	paccs := p.NewAccounts(page, accounts...)
+	return located(location, &jaccs, &yaccs, &xaccs, &paccs)
+}
+
+func (ar *AccountResource) Export(w http.ResponseWriter, request *http.Request) {
+
+	// determine the requested accounts.
+	er, err := newExportRequest(request.URL)
+	if err != nil {
+		writeError(w, request, ar.logger, newProblem(problemTypeInvalidFilter, err))
+		return
+	}
+
+	// choose the format of the export.
+	w.Header().Add("Vary", "Accept")
+	chosen, err := chooser.Choose(negotiable(request), exportRepresentations(er.posts)...)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+	if chosen == nil {
+		writeError(w, request, ar.logger, newProblem(problemTypeNotAcceptable, i18n.Errorf(
+			"resources.notAcceptable",
+			"resources: exports are available as %s", strings.Join(exportMediaTypes(), ", "))))
+		return
+	}
+
+	// stream the accounts as they are retrieved.
+	e := exporter{w: w, stream: chosen.(r.AccountStream)}
+	err = ar.accountService.Export(request.Context(), er.filter, er.posts, e.export)
+	if err == nil {
+		err = e.finish()
+	}
+	if err != nil && !e.committed() {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+	if err != nil {
+		e.abort(request, ar.logger, err)
+	}
+}
+
+func (ar *AccountResource) Import(w http.ResponseWriter, request *http.Request) {
+
+	// determine if the import is all or nothing.
+	atomic, err := boolParameter(request.URL.Query(), queryParameterAtomic)
+	if err != nil {
+		writeError(w, request, ar.logger, newProblem(problemTypeInvalidParameter, err))
+		return
+	}
+
+	// decode the records within the request body.
+	mediaType, b, err := readBody(w, request, ar.compression, keys(importDecoders))
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+	records, err := importDecoders[mediaType](b)
+	if err != nil {
+		writeError(w, request, ar.logger, newProblem(problemTypeMalformedBody, err))
+		return
+	}
+
+	// validate each record, reporting those that are invalid.
+	report := newImportReport(len(records))
+	now := time.Now()
+	accounts := []domain.Account{}
+	indexes := []int{}
+	for i, record := range records {
+		err := record.err
+		if err == nil {
+			var account domain.Account
+			if account, err = importedAccount(record.account, now); err == nil {
+				accounts = append(accounts, account)
+				indexes = append(indexes, i)
+				continue
+			}
+		}
+		report.record(i, record.account.UUID, app.ImportFailed, err)
+	}
+
+	// create the valid accounts, unless an all or nothing import has already
+	// failed.
+	aborted := atomic && report.Failed > 0
+	results, err := ar.accountService.Import(request.Context(), accounts, ar.batchSize, atomic, aborted)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+	for i, result := range results {
+		if _, ok := localized(i18n.DefaultLocale, result.Err); result.Err != nil && !ok {
+			infrastructure.LoggerFrom(request.Context(), ar.logger).Error("failed to import account",
+				zap.Int("index", indexes[i]),
+				zap.String("uuid", accounts[i].UUID().String()),
+				zap.Error(result.Err))
+		}
+		report.record(indexes[i], accounts[i].UUID(), result.Outcome, result.Err)
+	}
+
+	// negotiate amongst the report in every locale.
+	negotiated, reps := localize(request, func(locale string) []representation.Representation {
+		return importReportRepresentations(report.in(locale), locale)
+	})
+	representations, err := ar.compression.compress(reps...)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+	rec := newRecorder()
+	rec.Header().Add("Vary", "Accept-Language")
+	if err = ar.negotiation.negotiate(rec, negotiated, representations...); err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+
+	// the report is only known once every batch is saved, so it is saved
+	// as the response on its own.
+	respondWith(request, rec.status, rec.header, rec.body.Bytes())
+	if err = ar.idempotency.service.Save(request.Context()); err != nil {
+		writeError(w, request, ar.logger, err)
+		return
 	}
+	rec.writeTo(w)
+}
+
+// importReportRepresentations constructs the uncompressed representations
+// of the import report, written in the provided locale.
+func importReportRepresentations(
+	report r.ImportReport, locale string) []representation.Representation {
+	jrep := j.NewImportReport(report)
+	jrep.SetContentLanguage(locale)
+	yrep := y.NewImportReport(report)
+	yrep.SetContentLanguage(locale)
+	xrep := x.NewImportReport(report)
+	xrep.SetContentLanguage(locale)
+	prep := p.NewImportReport(report)
+	prep.SetContentLanguage(locale)
+	return []representation.Representation{&jrep, &yrep, &xrep, prep}
 }
 
 func (ar *AccountResource) CreateAndAppend(
 	w http.ResponseWriter, request *http.Request) {
 
-	// clean this part up.
-	representation := j.Account{}
-	if err := json.NewDecoder(request.Body).Decode(&representation); err != nil {
-		http.Error(w, err.Error(), 500)
+	// decode the request body.
+	representation, err := readAccount(w, request, ar.compression)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
@@ -115,7 +378,7 @@ func (ar *AccountResource) CreateAndAppend(
 			DeletedAt:  nil,
 		})
 		if err != nil {
-			http.Error(w, err.Error(), 500)
+			writeError(w, request, ar.logger, err)
 			return
 		}
 
@@ -132,18 +395,19 @@ func (ar *AccountResource) CreateAndAppend(
 		DeletedAt: nil,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
 	// create the account.
-	err = ar.accountService.Create(request.Context(), account)
+	uri, _ := request.URL.Parse("/" + account.UUID().String())
+	respond(request, 201, http.Header{"Content-Location": {uri.String()}})
+	err = ar.accountService.Create(request.Context(), account, representation.Password)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
-	uri, _ := request.URL.Parse("/" + account.UUID().String())
 	w.Header().Add("Content-Location", uri.String())
 	w.WriteHeader(201)
 }
@@ -151,27 +415,38 @@ func (ar *AccountResource) CreateAndAppend(
 func (ar *AccountResource) Replace(w http.ResponseWriter, request *http.Request) {
 	vars := mux.Vars(request)
 
-	// clean this part up.
-	representation := j.Account{}
-	if err := json.NewDecoder(request.Body).Decode(&representation); err != nil {
-		http.Error(w, err.Error(), 500)
+	// decode the request body.
+	representation, err := readAccount(w, request, ar.compression)
+	if err != nil {
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
 	now := time.Now()
 	posts := []domain.Post{}
 	for _, post := range representation.Posts {
+
+		// posts keep their identity across replacements.
+		postUUID := post.UUID
+		if postUUID == u.Nil {
+			postUUID = u.Must(u.NewV4())
+		}
+		createdAt := post.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = representation.CreatedAt
+		}
 		p, err := domain.NewPost(domain.PostParameters{
-			UUID:       u.Must(u.NewV4()),
+			UUID:       postUUID,
 			Title:      post.Title,
 			Content:    post.Content,
 			Draft:      post.Draft,
+			Likes:      post.Likes,
 			AuthorUUID: representation.UUID,
-			CreatedAt:  representation.CreatedAt,
+			CreatedAt:  createdAt,
 			UpdatedAt:  now,
 		})
 		if err != nil {
-			http.Error(w, err.Error(), 500)
+			writeError(w, request, ar.logger, err)
 			return
 		}
 
@@ -188,28 +463,66 @@ func (ar *AccountResource) Replace(w http.ResponseWriter, request *http.Request)
 		DeletedAt: nil,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
-	uuid := u.Must(u.FromString(vars["uuid"]))
+	uuid, err := u.FromString(vars["uuid"])
+	if err != nil {
+		writeError(w, request, ar.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
 	if account.UUID() != uuid {
-		http.Error(w, fmt.Errorf("mismatching UUIDs").Error(), 400)
+		writeError(w, request, ar.logger, newProblem(
+			problemTypeMismatchedUUID, errMismatchedUUID, fieldError{
+				field: "uuid",
+				cause: errMismatchedUUID,
+			}))
 		return
 	}
 
-	// retrieve the account.
-	existing, err := ar.accountService.Get(uuid)
+	// replace the account, provided the client is replacing its current
+	// state.
+	respond(request, 204, nil)
+	err = ar.accountService.Put(request.Context(), account, ar.precondition(request))
+	if err != nil {
+		writeError(w, request, ar.logger, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (ar *AccountResource) Patch(w http.ResponseWriter, request *http.Request) {
+	vars := mux.Vars(request)
+
+	// retrieve the account uuid.
+	uuid, err := u.FromString(vars["uuid"])
 	if err != nil {
-		http.Error(w, err.Error(), 404)
+		writeError(w, request, ar.logger, newProblem(problemTypeMalformedUUID, err))
 		return
 	}
 
-	// upsert the account.
-	account.SetCreatedAt(existing.CreatedAt())
-	err = ar.accountService.Put(request.Context(), account)
+	mediaType, document, err := readPatch(w, request, ar.compression)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, request, ar.logger, err)
+		return
+	}
+
+	// apply the patch to the current state of the account.
+	respond(request, 204, nil)
+	err = ar.accountService.Alter(request.Context(), uuid, func(account *domain.Account) error {
+		if err := ar.precondition(request)(account); err != nil {
+			return err
+		}
+		patched := j.Account{}
+		if err := applyPatch(j.NewAccount(*account), mediaType, document, &patched); err != nil {
+			return err
+		}
+		return applyAccount(account, patched, time.Now())
+	})
+	if err != nil {
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
@@ -220,17 +533,17 @@ func (ar *AccountResource) Delete(w http.ResponseWriter, request *http.Request)
 	vars := mux.Vars(request)
 
 	// retrieve the account uuid.
-	uuid := u.Must(u.FromString(vars["uuid"]))
-	account, err := ar.accountService.Get(uuid)
+	uuid, err := u.FromString(vars["uuid"])
 	if err != nil {
-		http.Error(w, err.Error(), 404)
+		writeError(w, request, ar.logger, newProblem(problemTypeMalformedUUID, err))
 		return
 	}
 
-	// delete the account.
-	err = ar.accountService.Delete(request.Context(), account)
+	// delete the account, provided the client is deleting its current state.
+	respond(request, 204, nil)
+	err = ar.accountService.Delete(request.Context(), uuid, ar.precondition(request))
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, request, ar.logger, err)
 		return
 	}
 
@@ -1,52 +1,245 @@
 package resources
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/freerware/tutor/api/i18n"
+	"github.com/freerware/tutor/api/openapi"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	nd "github.com/freerware/tutor/api/representations/ndjson"
 	"github.com/freerware/tutor/api/server"
+	"github.com/freerware/tutor/domain"
 )
 
 func (ar *AccountResource) MuxConfiguration() (config server.MuxConfiguration) {
+	accounts := negotiated(j.Accounts{}, accountsRepresentations(r.Page{}, url.URL{})...)
+	account := negotiated(j.Account{}, accountRepresentations(domain.Account{}, url.URL{})...)
+	report := negotiated(
+		j.ImportReport{}, importReportRepresentations(r.ImportReport{}, i18n.DefaultLocale)...)
+	list := openapi.Operation{
+		Summary:    "Retrieve a page of accounts",
+		Parameters: pageParameters,
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusOK, Headers: []string{"Link"}, Content: accounts},
+			multipleChoices(),
+		}, http.StatusBadRequest, http.StatusNotAcceptable),
+	}
+	listVariant := variantOperation("Retrieve a page of accounts in a single format", list)
+	export := openapi.Operation{
+		Summary: "Export every account",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        queryParameterPosts,
+				In:          "query",
+				Description: "Whether the posts of each account are included.",
+				Schema:      false,
+			},
+			{
+				Name:        queryParameterCreatedAfter,
+				In:          "query",
+				Description: "Only accounts created at or after this RFC 3339 time are included.",
+				Schema:      "",
+			},
+			{
+				Name:        queryParameterCreatedBefore,
+				In:          "query",
+				Description: "Only accounts created before this RFC 3339 time are included.",
+				Schema:      "",
+			},
+			{
+				Name:        queryParameterUpdatedAfter,
+				In:          "query",
+				Description: "Only accounts modified at or after this RFC 3339 time are included.",
+				Schema:      "",
+			},
+			{
+				Name:        queryParameterDeleted,
+				In:          "query",
+				Description: "Whether only deleted, or only remaining, accounts are included.",
+				Schema:      false,
+			},
+		},
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusOK, Content: &openapi.Content{MediaTypes: exportMediaTypes(), Schema: nd.Account{}}},
+		}, http.StatusBadRequest, http.StatusNotAcceptable),
+	}
+	bulkImport := secured(idempotent(openapi.Operation{
+		Summary: "Import accounts in bulk",
+		Parameters: []openapi.Parameter{
+			{
+				Name:        queryParameterAtomic,
+				In:          "query",
+				Description: "Whether no accounts are created if any of them cannot be.",
+				Schema:      false,
+			},
+		},
+		RequestBody: &openapi.Content{MediaTypes: keys(importDecoders), Schema: []j.Account{}},
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusOK, Content: report},
+			multipleChoices(),
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotAcceptable,
+			http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType),
+	}))
+	get := openapi.Operation{
+		Summary:    "Retrieve an existing account",
+		Parameters: conditionalParameters,
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusOK, Headers: []string{"ETag", "Last-Modified"}, Content: account},
+			{Status: http.StatusNotModified},
+			multipleChoices(),
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable, http.StatusPreconditionFailed),
+	}
+	getVariant := variantOperation("Retrieve an existing account in a single format", get)
+	replace := secured(idempotent(openapi.Operation{
+		Summary:     "Replace an existing account",
+		Parameters:  conditionalParameters,
+		RequestBody: &openapi.Content{MediaTypes: keys(accountDecoders), Schema: j.Account{}},
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusNoContent},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+			http.StatusPreconditionFailed, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity),
+	}))
+	modify := secured(idempotent(openapi.Operation{
+		Summary:     "Modify an existing account",
+		Parameters:  conditionalParameters,
+		RequestBody: patchDocument(),
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusNoContent},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+			http.StatusPreconditionFailed, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity),
+	}))
+	remove := secured(idempotent(openapi.Operation{
+		Summary:    "Remove an existing account",
+		Parameters: conditionalParameters,
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusNoContent},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusPreconditionFailed),
+	}))
+	create := idempotent(openapi.Operation{
+		Summary:     "Create a new account",
+		RequestBody: &openapi.Content{MediaTypes: keys(accountDecoders), Schema: j.Account{}},
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusCreated, Headers: []string{"Content-Location"}},
+		}, http.StatusBadRequest, http.StatusConflict, http.StatusRequestEntityTooLarge,
+			http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity),
+	})
+
+	// unsafe requests can be retried with an idempotency key, and those
+	// that change existing accounts, or import them, must be authenticated.
+	retryable := []server.Middleware{ar.idempotency.middleware()}
+	unsafe := []server.Middleware{authenticated(ar.logger), ar.idempotency.middleware()}
+
 	config = server.MuxConfiguration{
 		PathPrefix: "/accounts",
 		Handlers: []server.HandlerConfiguration{
+			{
+				Path:        "/export",
+				HandlerFunc: ar.Export,
+				Methods:     []string{"GET"},
+				Operation:   export,
+			},
+			{
+				Path:        "/import",
+				HandlerFunc: ar.Import,
+				Middlewares: unsafe,
+				Methods:     []string{"POST"},
+				Operation:   bulkImport,
+			},
+			{
+				Path:        "." + variantFormat,
+				HandlerFunc: ar.List,
+				Methods:     []string{"GET"},
+				Operation:   listVariant,
+			},
+			{
+				Path:        "/{uuid}." + variantFormat,
+				HandlerFunc: ar.Get,
+				Methods:     []string{"GET"},
+				Operation:   getVariant,
+			},
+			{
+				Path:        "",
+				HandlerFunc: ar.List,
+				Methods:     []string{"GET"},
+				Operation:   list,
+			},
+			{
+				Path:        "/",
+				HandlerFunc: ar.List,
+				Methods:     []string{"GET"},
+				Operation:   list,
+			},
 			{
 				Path:        "/{uuid}/",
 				HandlerFunc: ar.Get,
 				Methods:     []string{"GET"},
+				Operation:   get,
 			},
 			{
 				Path:        "/{uuid}",
 				HandlerFunc: ar.Get,
 				Methods:     []string{"GET"},
+				Operation:   get,
 			},
 			{
 				Path:        "/{uuid}",
 				HandlerFunc: ar.Replace,
+				Middlewares: unsafe,
 				Methods:     []string{"PUT"},
+				Operation:   replace,
 			},
 			{
 				Path:        "/{uuid}/",
 				HandlerFunc: ar.Replace,
+				Middlewares: unsafe,
 				Methods:     []string{"PUT"},
+				Operation:   replace,
+			},
+			{
+				Path:        "/{uuid}",
+				HandlerFunc: ar.Patch,
+				Middlewares: unsafe,
+				Methods:     []string{"PATCH"},
+				Operation:   modify,
+			},
+			{
+				Path:        "/{uuid}/",
+				HandlerFunc: ar.Patch,
+				Middlewares: unsafe,
+				Methods:     []string{"PATCH"},
+				Operation:   modify,
 			},
 			{
 				Path:        "/{uuid}",
 				HandlerFunc: ar.Delete,
+				Middlewares: unsafe,
 				Methods:     []string{"DELETE"},
+				Operation:   remove,
 			},
 			{
 				Path:        "/{uuid}/",
 				HandlerFunc: ar.Delete,
+				Middlewares: unsafe,
 				Methods:     []string{"DELETE"},
+				Operation:   remove,
 			},
 			{
 				Path:        "",
 				HandlerFunc: ar.CreateAndAppend,
+				Middlewares: retryable,
 				Methods:     []string{"POST"},
+				Operation:   create,
 			},
 			{
 				Path:        "/",
 				HandlerFunc: ar.CreateAndAppend,
+				Middlewares: retryable,
 				Methods:     []string{"POST"},
+				Operation:   create,
 			},
 		},
 	}
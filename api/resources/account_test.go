@@ -0,0 +1,55 @@
+package resources
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+)
+
+func TestAccountPrecondition(t *testing.T) {
+	now := time.Now()
+	account := domain.ReconstituteAccount(domain.AccountParameters{
+		UUID:      u.Must(u.NewV4()),
+		Username:  "jdoe",
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		account *domain.Account
+		failed  bool
+	}{
+		{name: "unconditional creation", account: nil},
+		{name: "creation if none match", header: "If-None-Match", value: "*", account: nil},
+		{name: "creation if match", header: "If-Match", value: "*", account: nil, failed: true},
+		{name: "replacement if none match", header: "If-None-Match", value: "*", account: &account, failed: true},
+		{name: "replacement if match", header: "If-Match", value: "*", account: &account},
+		{name: "replacement if mismatched", header: "If-Match", value: `"stale"`, account: &account, failed: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPut, "/accounts/"+account.UUID().String(), nil)
+			if test.header != "" {
+				request.Header.Set(test.header, test.value)
+			}
+			ar := AccountResource{}
+
+			err := ar.precondition(request)(test.account)
+
+			var p *problem
+			if failed := errors.As(err, &p) && p.status == http.StatusPreconditionFailed; failed != test.failed {
+				t.Fatalf("expected the precondition to fail to be %t, got %v", test.failed, err)
+			}
+			if !test.failed && err != nil {
+				t.Fatalf("expected the precondition to hold, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"net/http"
+
+	"github.com/freerware/tutor/api/openapi"
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// authenticationRealm is the protection space that clients are challenged
+// to authenticate within.
+const authenticationRealm = "tutor"
+
+type AuthenticationParameters struct {
+	fx.In
+
+	AuthenticationService app.AuthenticationService
+	Logger                *zap.Logger
+}
+
+// NewAuthentication constructs the middleware that authenticates requests
+// bearing an Authorization header, with either Basic credentials or a bearer
+// token. The principal of an authenticated request is available to the
+// handlers within the context of the request, and requests bearing invalid
+// credentials are rejected. Requests without credentials are left to the
+// handlers, which decide whether they require a principal.
+func NewAuthentication(parameters AuthenticationParameters) server.MiddlewareResult {
+	service, logger := parameters.AuthenticationService, parameters.Logger
+	return server.MiddlewareResult{Middleware: server.Middleware{
+		Name:  "authentication",
+		Order: 1,
+		Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+				authorization := request.Header.Get("Authorization")
+				if authorization == "" {
+					next.ServeHTTP(w, request)
+					return
+				}
+				principal, err := service.Identify(request.Context(), authorization)
+				if err != nil {
+					writeError(w, request, logger, err)
+					return
+				}
+				ctx := app.WithPrincipal(request.Context(), principal)
+				ctx = infrastructure.WithLogger(ctx, infrastructure.LoggerFrom(ctx, logger).With(
+					zap.Stringer("principal", principal.AccountUUID)))
+				next.ServeHTTP(w, request.WithContext(ctx))
+			})
+		},
+	}}
+}
+
+// authenticated requires that requests to the handler are made on behalf of
+// a principal, who authenticated in one of the provided ways when any are
+// provided.
+func authenticated(logger *zap.Logger, ways ...string) server.Middleware {
+	return server.Middleware{
+		Name:  "authenticated",
+		Order: -1,
+		Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+				principal, ok := app.PrincipalFrom(request.Context())
+				if !ok || !authenticatedBy(principal, ways...) {
+					writeError(w, request, logger, app.ErrUnauthenticated)
+					return
+				}
+				next.ServeHTTP(w, request)
+			})
+		},
+	}
+}
+
+// authenticatedBy indicates if the principal authenticated in one of the
+// provided ways, which is always the case when none are provided.
+func authenticatedBy(principal app.Principal, ways ...string) bool {
+	if len(ways) == 0 {
+		return true
+	}
+	for _, way := range ways {
+		if principal.AuthenticatedBy == way {
+			return true
+		}
+	}
+	return false
+}
+
+// secured describes the security schemes that the provided operation
+// requires, along with the problems caused by lacking them.
+func secured(o openapi.Operation, schemes ...string) openapi.Operation {
+	if len(schemes) == 0 {
+		schemes = []string{openapi.SecurityBasic, openapi.SecurityBearer}
+	}
+	o.Security = schemes
+	o.Responses = append(append([]openapi.Response{}, o.Responses...), problems(http.StatusUnauthorized)...)
+	return o
+}
@@ -0,0 +1,293 @@
+package resources
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/freerware/tutor/api/i18n"
+	j "github.com/freerware/tutor/api/representations/json"
+	p "github.com/freerware/tutor/api/representations/protobuf"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+	x "github.com/freerware/tutor/api/representations/xml"
+	y "github.com/freerware/tutor/api/representations/yaml"
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+)
+
+// accountDecoders decodes account representations of each supported media
+// type into their JSON counterpart, which the handlers operate on.
+var accountDecoders = map[string]func(string, []byte) (j.Account, error){
+	"application/json":       jsonAccount,
+	"application/xml":        xmlAccount,
+	"application/yaml":       yamlAccount,
+	"text/yaml":              yamlAccount,
+	"application/protobuf":   protobufAccount,
+	"application/x-protobuf": protobufAccount,
+}
+
+// postDecoders decodes post representations of each supported media type
+// into their JSON counterpart, which the handlers operate on.
+var postDecoders = map[string]func(string, []byte) (j.Post, error){
+	"application/json":       jsonPost,
+	"application/xml":        xmlPost,
+	"application/yaml":       yamlPost,
+	"text/yaml":              yamlPost,
+	"application/protobuf":   protobufPost,
+	"application/x-protobuf": protobufPost,
+}
+
+// passwordChangeDecoders decodes password change representations of each
+// supported media type into their JSON counterpart, which the handlers
+// operate on.
+var passwordChangeDecoders = map[string]func(string, []byte) (j.PasswordChange, error){
+	"application/json": jsonPasswordChange,
+	"application/xml":  xmlPasswordChange,
+	"application/yaml": yamlPasswordChange,
+	"text/yaml":        yamlPasswordChange,
+}
+
+// readAccount decodes the account representation within the request body
+// according to its media type.
+func readAccount(
+	w http.ResponseWriter, request *http.Request, c compression) (j.Account, error) {
+	mediaType, b, err := readBody(w, request, c, keys(accountDecoders))
+	if err != nil {
+		return j.Account{}, err
+	}
+	account, err := accountDecoders[mediaType](mediaType, b)
+	if err != nil {
+		return j.Account{}, newProblem(problemTypeMalformedBody, err)
+	}
+	return account, nil
+}
+
+// readPost decodes the post representation within the request body
+// according to its media type.
+func readPost(
+	w http.ResponseWriter, request *http.Request, c compression) (j.Post, error) {
+	mediaType, b, err := readBody(w, request, c, keys(postDecoders))
+	if err != nil {
+		return j.Post{}, err
+	}
+	post, err := postDecoders[mediaType](mediaType, b)
+	if err != nil {
+		return j.Post{}, newProblem(problemTypeMalformedBody, err)
+	}
+	return post, nil
+}
+
+// readPasswordChange decodes the password change representation within the
+// request body according to its media type.
+func readPasswordChange(
+	w http.ResponseWriter, request *http.Request, c compression) (j.PasswordChange, error) {
+	mediaType, b, err := readBody(w, request, c, keys(passwordChangeDecoders))
+	if err != nil {
+		return j.PasswordChange{}, err
+	}
+	change, err := passwordChangeDecoders[mediaType](mediaType, b)
+	if err != nil {
+		return j.PasswordChange{}, newProblem(problemTypeMalformedBody, err)
+	}
+	return change, nil
+}
+
+// readBody retrieves the media type and decompressed contents of the
+// request body, ensuring that the media type is one of those supported. The
+// supported media types are advertised when it is not.
+func readBody(
+	w http.ResponseWriter,
+	request *http.Request,
+	c compression,
+	supported []string) (string, []byte, error) {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err == nil {
+		for _, s := range supported {
+			if mediaType == s {
+				b, err := c.read(w, request)
+				if err != nil {
+					return "", nil, err
+				}
+				return mediaType, b, nil
+			}
+		}
+	}
+	w.Header().Set("Accept", strings.Join(supported, ", "))
+	return "", nil, newProblem(problemTypeUnsupportedMediaType, i18n.Errorf(
+		"resources.unsupportedMediaType",
+		"resources: request bodies must be one of %s", strings.Join(supported, ", ")))
+}
+
+// keys provides the media types of the provided decoders in a stable order.
+func keys[T any](decoders map[string]T) []string {
+	mediaTypes := make([]string, 0, len(decoders))
+	for mediaType := range decoders {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	return mediaTypes
+}
+
+func jsonAccount(mediaType string, b []byte) (j.Account, error) {
+	rep := j.NewAccount(domain.Account{})
+	rep.SetContentType(mediaType)
+	err := rep.FromBytes(b)
+	return rep, err
+}
+
+func xmlAccount(mediaType string, b []byte) (j.Account, error) {
+	rep := x.NewAccount(domain.Account{})
+	rep.SetContentType(mediaType)
+	if err := rep.FromBytes(b); err != nil {
+		return j.Account{}, err
+	}
+	posts := make([]j.Post, len(rep.Posts))
+	for i, post := range rep.Posts {
+		posts[i] = j.Post(post)
+	}
+	return j.Account{
+		UUID:              rep.UUID,
+		PrimaryCredential: rep.PrimaryCredential,
+		GivenName:         rep.GivenName,
+		Surname:           rep.Surname,
+		Posts:             posts,
+		CreatedAt:         rep.CreatedAt,
+		UpdatedAt:         rep.UpdatedAt,
+		DeletedAt:         rep.DeletedAt,
+		Password:          rep.Password,
+	}, nil
+}
+
+func yamlAccount(mediaType string, b []byte) (j.Account, error) {
+	rep := y.NewAccount(domain.Account{})
+	rep.SetContentType(mediaType)
+	if err := rep.FromBytes(b); err != nil {
+		return j.Account{}, err
+	}
+	posts := make([]j.Post, len(rep.Posts))
+	for i, post := range rep.Posts {
+		posts[i] = j.Post(post)
+	}
+	return j.Account{
+		UUID:              rep.UUID,
+		PrimaryCredential: rep.PrimaryCredential,
+		GivenName:         rep.GivenName,
+		Surname:           rep.Surname,
+		Posts:             posts,
+		CreatedAt:         rep.CreatedAt,
+		UpdatedAt:         rep.UpdatedAt,
+		DeletedAt:         rep.DeletedAt,
+		Password:          rep.Password,
+	}, nil
+}
+
+func protobufAccount(mediaType string, b []byte) (j.Account, error) {
+	rep := p.NewAccount(domain.Account{})
+	rep.SetContentType(mediaType)
+	if err := rep.FromBytes(b); err != nil {
+		return j.Account{}, err
+	}
+	uuid, err := protobufUUID(rep.UUID)
+	if err != nil {
+		return j.Account{}, err
+	}
+	posts := make([]j.Post, len(rep.Posts))
+	for i, post := range rep.Posts {
+		if posts[i], err = fromProtobufPost(post); err != nil {
+			return j.Account{}, err
+		}
+	}
+	return j.Account{
+		UUID:              uuid,
+		PrimaryCredential: rep.Username,
+		GivenName:         rep.GivenName,
+		Surname:           rep.Surname,
+		Posts:             posts,
+		CreatedAt:         p.Time(rep.CreatedAt),
+		UpdatedAt:         p.Time(rep.UpdatedAt),
+		DeletedAt:         p.TimePtr(rep.DeletedAt),
+		Password:          rep.Password,
+	}, nil
+}
+
+func jsonPost(mediaType string, b []byte) (j.Post, error) {
+	rep := j.NewPost(domain.Post{})
+	rep.SetContentType(mediaType)
+	err := rep.FromBytes(b)
+	return rep, err
+}
+
+func xmlPost(mediaType string, b []byte) (j.Post, error) {
+	rep := x.NewPost(domain.Post{})
+	rep.SetContentType(mediaType)
+	err := rep.FromBytes(b)
+	return j.Post(rep), err
+}
+
+func yamlPost(mediaType string, b []byte) (j.Post, error) {
+	rep := y.NewPost(domain.Post{})
+	rep.SetContentType(mediaType)
+	err := rep.FromBytes(b)
+	return j.Post(rep), err
+}
+
+func protobufPost(mediaType string, b []byte) (j.Post, error) {
+	rep := p.NewPost(domain.Post{})
+	rep.SetContentType(mediaType)
+	if err := rep.FromBytes(b); err != nil {
+		return j.Post{}, err
+	}
+	return fromProtobufPost(rep.Post)
+}
+
+func fromProtobufPost(post *gen.Post) (j.Post, error) {
+	uuid, err := protobufUUID(post.UUID)
+	if err != nil {
+		return j.Post{}, err
+	}
+	return j.Post{
+		UUID:      uuid,
+		Title:     post.Title,
+		Content:   post.Content,
+		Draft:     post.IsDraft,
+		Likes:     int(post.Likes),
+		CreatedAt: p.Time(post.CreatedAt),
+		UpdatedAt: p.Time(post.UpdatedAt),
+		DeletedAt: p.TimePtr(post.DeletedAt),
+	}, nil
+}
+
+func jsonPasswordChange(mediaType string, b []byte) (j.PasswordChange, error) {
+	rep := j.NewPasswordChange()
+	rep.SetContentType(mediaType)
+	err := rep.FromBytes(b)
+	return rep, err
+}
+
+func xmlPasswordChange(mediaType string, b []byte) (j.PasswordChange, error) {
+	rep := x.NewPasswordChange()
+	rep.SetContentType(mediaType)
+	if err := rep.FromBytes(b); err != nil {
+		return j.PasswordChange{}, err
+	}
+	return j.PasswordChange{CurrentPassword: rep.CurrentPassword, Password: rep.Password}, nil
+}
+
+func yamlPasswordChange(mediaType string, b []byte) (j.PasswordChange, error) {
+	rep := y.NewPasswordChange()
+	rep.SetContentType(mediaType)
+	if err := rep.FromBytes(b); err != nil {
+		return j.PasswordChange{}, err
+	}
+	return j.PasswordChange{CurrentPassword: rep.CurrentPassword, Password: rep.Password}, nil
+}
+
+// protobufUUID parses the provided UUID, treating its absence as the nil
+// UUID.
+func protobufUUID(uuid string) (u.UUID, error) {
+	if uuid == "" {
+		return u.Nil, nil
+	}
+	return u.FromString(uuid)
+}
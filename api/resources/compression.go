@@ -0,0 +1,130 @@
+package resources
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/encoding"
+	"github.com/freerware/tutor/api/i18n"
+	"github.com/freerware/tutor/config"
+)
+
+// compression compresses the representations that are served, and
+// decompresses the request bodies that are received.
+type compression struct {
+	minimumSize        int
+	maximumRequestSize int
+}
+
+// newCompression constructs the compression described by the provided
+// configuration.
+func newCompression(c config.CompressionConfiguration) compression {
+	return compression{
+		minimumSize:        c.MinimumSize,
+		maximumRequestSize: c.MaximumRequestSize,
+	}
+}
+
+// compress complements the provided representations with their compressed
+// counterparts, leaving the negotiator to pick the content coding. The
+// representations smaller than the minimum size are not worth compressing.
+func (c compression) compress(
+	reps ...representation.Representation) ([]representation.Representation, error) {
+	compressed := append([]representation.Representation{}, reps...)
+	for _, rep := range reps {
+		b, err := rep.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(b) < c.minimumSize {
+			continue
+		}
+		for _, coding := range encoding.Codings {
+			compressed = append(compressed, &encoded{Representation: rep, coding: coding})
+		}
+	}
+	return compressed, nil
+}
+
+// read retrieves the request body, reversing the content codings applied to
+// it. The supported content codings are advertised when any of them is not.
+func (c compression) read(w http.ResponseWriter, request *http.Request) ([]byte, error) {
+	var codings []string
+	for _, value := range request.Header.Values("Content-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			if coding = strings.TrimSpace(coding); coding == "" {
+				continue
+			}
+			if !encoding.Supported(coding) {
+				w.Header().Set("Accept-Encoding", strings.Join(encoding.Codings, ", "))
+				return nil, newProblem(problemTypeUnsupportedContentCoding, i18n.Errorf(
+					"resources.unsupportedContentCoding",
+					"resources: request bodies must be encoded with %s",
+					strings.Join(encoding.Codings, ", ")))
+			}
+			codings = append(codings, coding)
+		}
+	}
+	body, err := encoding.NewReader(request.Body, codings...)
+	if err != nil {
+		return nil, newProblem(problemTypeMalformedBody, err)
+	}
+	defer body.Close()
+
+	// guard against bodies that decompress far beyond their transferred size.
+	var reader io.Reader = body
+	if c.maximumRequestSize > 0 {
+		reader = io.LimitReader(body, int64(c.maximumRequestSize)+1)
+	}
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, newProblem(problemTypeMalformedBody, err)
+	}
+	if c.maximumRequestSize > 0 && len(b) > c.maximumRequestSize {
+		return nil, newProblem(problemTypeRequestTooLarge, i18n.Errorf(
+			"resources.requestTooLarge",
+			"resources: request bodies must not exceed %d bytes", c.maximumRequestSize))
+	}
+	return b, nil
+}
+
+// encoded is a representation compressed with a content coding. It carries
+// validators of its own, since they must differ from those of the
+// uncompressed representation.
+type encoded struct {
+	representation.Representation
+
+	coding       string
+	b            []byte
+	etag         *string
+	lastModified *time.Time
+}
+
+// ContentEncoding retrieves the content coding of the representation.
+func (e *encoded) ContentEncoding() []string { return []string{e.coding} }
+
+// Bytes provides the compressed representation as bytes.
+func (e *encoded) Bytes() ([]byte, error) {
+	if e.b != nil {
+		return e.b, nil
+	}
+	b, err := e.Representation.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if e.b, err = encoding.Encode(e.coding, b); err != nil {
+		return nil, err
+	}
+	return e.b, nil
+}
+
+func (e *encoded) ETag() *string { return e.etag }
+
+func (e *encoded) SetETag(tag string) { e.etag = &tag }
+
+func (e *encoded) LastModified() *time.Time { return e.lastModified }
+
+func (e *encoded) SetLastModified(t time.Time) { e.lastModified = &t }
@@ -0,0 +1,172 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/freerware/negotiator/proactive"
+	"github.com/freerware/negotiator/representation"
+)
+
+// chooser selects the representation that proactive negotiation would
+// serve, so that its validators can be evaluated beforehand.
+var chooser = proactive.ApacheHTTPD()
+
+// validatable represents a representation that carries validators.
+type validatable interface {
+	representation.Representation
+
+	ETag() *string
+	SetETag(string)
+	LastModified() *time.Time
+	SetLastModified(time.Time)
+}
+
+// validate assigns validators to each of the provided representations.
+//
+// The entity tag of each representation is a digest of its serialized form,
+// which makes it a strong validator: it changes whenever the state of the
+// resource changes, and differs between representations of the same state.
+// Compressed representations are tagged with the digest of the
+// representation they compress along with their content coding, so that
+// only the representation that is served is ever compressed.
+func validate(
+	lastModified time.Time,
+	reps ...representation.Representation) ([]representation.Representation, error) {
+	for _, rep := range reps {
+		v, ok := rep.(validatable)
+		if !ok {
+			continue
+		}
+		etag, err := entityTag(rep)
+		if err != nil {
+			return nil, err
+		}
+		v.SetETag(etag)
+		v.SetLastModified(lastModified.UTC().Truncate(time.Second))
+	}
+	return reps, nil
+}
+
+// entityTag computes the entity tag of the provided representation, reusing
+// the one it has already been assigned.
+func entityTag(rep representation.Representation) (string, error) {
+	if e, ok := rep.(*encoded); ok {
+		etag, err := entityTag(e.Representation)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(etag, `"`) + "-" + e.coding + `"`, nil
+	}
+	if v, ok := rep.(validatable); ok && v.ETag() != nil {
+		return *v.ETag(), nil
+	}
+	b, err := rep.Bytes()
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(digest[:16]) + `"`, nil
+}
+
+// writeValidators writes the validators of the provided representation to
+// the response headers.
+func writeValidators(w http.ResponseWriter, rep representation.Representation) {
+	v, ok := rep.(validatable)
+	if !ok {
+		return
+	}
+	if etag := v.ETag(); etag != nil {
+		w.Header().Set("ETag", *etag)
+	}
+	if lastModified := v.LastModified(); lastModified != nil {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+}
+
+// preconditions evaluates the conditional headers of the request (RFC 9110,
+// section 13.2.2) against the current representations of the resource. The
+// selected representation is the one that would be served to a safe
+// request, and is nil when there is none. A zero status code indicates that
+// the request should proceed; otherwise, the request must be answered with
+// the returned status code.
+func preconditions(
+	request *http.Request,
+	selected representation.Representation,
+	reps ...representation.Representation) int {
+
+	safe := request.Method == http.MethodGet || request.Method == http.MethodHead
+	header := request.Header
+
+	if ifMatch := header.Get("If-Match"); ifMatch != "" {
+		if !matches(ifMatch, true, reps...) {
+			return http.StatusPreconditionFailed
+		}
+	} else if ius, err := http.ParseTime(header.Get("If-Unmodified-Since")); err == nil {
+		if modifiedSince(ius, reps...) {
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	candidates := reps
+	if safe && selected != nil {
+		candidates = []representation.Representation{selected}
+	}
+	if ifNoneMatch := header.Get("If-None-Match"); ifNoneMatch != "" {
+		if matches(ifNoneMatch, false, candidates...) {
+			if safe {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	} else if ims, err := http.ParseTime(header.Get("If-Modified-Since")); err == nil && safe {
+		if len(candidates) > 0 && !modifiedSince(ims, candidates...) {
+			return http.StatusNotModified
+		}
+	}
+	return 0
+}
+
+// matches indicates if the provided entity tag list matches any of the
+// representations, using either the strong or weak comparison function.
+func matches(list string, strong bool, reps ...representation.Representation) bool {
+	if strings.TrimSpace(list) == "*" {
+		return len(reps) > 0
+	}
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		weak := strings.HasPrefix(tag, "W/")
+		if weak && strong {
+			continue
+		}
+		tag = strings.TrimPrefix(tag, "W/")
+		for _, rep := range reps {
+			v, ok := rep.(validatable)
+			if !ok || v.ETag() == nil {
+				continue
+			}
+			if *v.ETag() == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// modifiedSince indicates if any of the representations have been modified
+// after the provided time.
+func modifiedSince(t time.Time, reps ...representation.Representation) bool {
+	for _, rep := range reps {
+		v, ok := rep.(validatable)
+		if !ok || v.LastModified() == nil {
+			continue
+		}
+		if v.LastModified().After(t) {
+			return true
+		}
+	}
+	return false
+}
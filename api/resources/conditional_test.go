@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/encoding"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+)
+
+func TestValidateTagsCompressedRepresentationsWithoutCompressing(t *testing.T) {
+	token := j.NewToken(r.Token{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 3600})
+	reps := []representation.Representation{&token}
+	for _, coding := range encoding.Codings {
+		reps = append(reps, &encoded{Representation: &token, coding: coding})
+	}
+
+	if _, err := validate(time.Now(), reps...); err != nil {
+		t.Fatalf("failed to validate: %v", err)
+	}
+
+	identity := *token.ETag()
+	seen := map[string]bool{identity: true}
+	for _, rep := range reps[1:] {
+		e := rep.(*encoded)
+		if e.b != nil {
+			t.Errorf("%s representation was compressed", e.coding)
+		}
+		etag := *e.ETag()
+		if expected := identity[:len(identity)-1] + "-" + e.coding + `"`; etag != expected {
+			t.Errorf("expected %s representation to be tagged %s, got %s", e.coding, expected, etag)
+		}
+		if seen[etag] {
+			t.Errorf("%s representation shares the entity tag %s", e.coding, etag)
+		}
+		seen[etag] = true
+	}
+}
@@ -0,0 +1,171 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	c "github.com/freerware/tutor/api/representations/csv"
+	nd "github.com/freerware/tutor/api/representations/ndjson"
+	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/zap"
+)
+
+const (
+	queryParameterPosts         = "posts"
+	queryParameterCreatedAfter  = "createdAfter"
+	queryParameterCreatedBefore = "createdBefore"
+	queryParameterUpdatedAfter  = "updatedAfter"
+	queryParameterDeleted       = "deleted"
+)
+
+// exportFlushInterval is the number of accounts written between flushes of
+// an export to the client.
+const exportFlushInterval = 100
+
+// exportRequest represents the accounts requested for export by a client.
+type exportRequest struct {
+	filter infrastructure.AccountFilter
+	posts  bool
+}
+
+// newExportRequest interprets the export query parameters of the provided URL.
+func newExportRequest(u *url.URL) (exportRequest, error) {
+	query := u.Query()
+	er := exportRequest{}
+	var err error
+	if er.posts, err = boolParameter(query, queryParameterPosts); err != nil {
+		return exportRequest{}, err
+	}
+	times := map[string]**time.Time{
+		queryParameterCreatedAfter:  &er.filter.CreatedAfter,
+		queryParameterCreatedBefore: &er.filter.CreatedBefore,
+		queryParameterUpdatedAfter:  &er.filter.UpdatedAfter,
+	}
+	for name, criterion := range times {
+		s := query.Get(name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return exportRequest{}, invalidParameter(name)
+		}
+		*criterion = &t
+	}
+	if query.Get(queryParameterDeleted) != "" {
+		deleted, err := boolParameter(query, queryParameterDeleted)
+		if err != nil {
+			return exportRequest{}, err
+		}
+		er.filter.Deleted = &deleted
+	}
+	return er, nil
+}
+
+func boolParameter(query url.Values, name string) (bool, error) {
+	s := query.Get(name)
+	if s == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, invalidParameter(name)
+	}
+	return b, nil
+}
+
+func invalidParameter(name string) error {
+	return i18n.Errorf("resources.invalidParameter", "resources: query parameter %s is malformed", name)
+}
+
+// exportRepresentations constructs the available representations of an
+// account export.
+func exportRepresentations(posts bool) []representation.Representation {
+	return []representation.Representation{nd.NewAccounts(posts), c.NewAccounts(posts)}
+}
+
+// exporter writes the accounts of an export as they are retrieved. The
+// response is only committed once the first account has been retrieved, so
+// that failures to begin the export can still be described to the client.
+type exporter struct {
+	w       http.ResponseWriter
+	stream  r.AccountStream
+	encoder r.AccountEncoder
+	count   int
+}
+
+func (e *exporter) export(account domain.Account) error {
+	if e.encoder == nil {
+		e.start()
+	}
+	if err := e.encoder.Encode(account); err != nil {
+		return err
+	}
+	if e.count++; e.count%exportFlushInterval == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// committed indicates if the response has been committed.
+func (e *exporter) committed() bool {
+	return e.encoder != nil
+}
+
+// start commits the response.
+func (e *exporter) start() {
+	contentType := e.stream.ContentType()
+	if charset := e.stream.ContentCharset(); charset != "" {
+		contentType += "; charset=" + charset
+	}
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.WriteHeader(http.StatusOK)
+	e.encoder = e.stream.Encoder(e.w)
+}
+
+// flush writes the buffered accounts to the client.
+func (e *exporter) flush() error {
+	if err := e.encoder.Flush(); err != nil {
+		return err
+	}
+	if err := http.NewResponseController(e.w).Flush(); err != http.ErrNotSupported {
+		return err
+	}
+	return nil
+}
+
+// finish completes the export, responding with an empty collection if no
+// accounts were retrieved.
+func (e *exporter) finish() error {
+	if e.encoder == nil {
+		e.start()
+	}
+	return e.flush()
+}
+
+// abort terminates an export that failed after the response was committed.
+// The connection is closed, rather than the response completed, so that
+// clients do not mistake the partial export for a complete one.
+func (e *exporter) abort(request *http.Request, logger *zap.Logger, err error) {
+	infrastructure.LoggerFrom(request.Context(), logger).Error("failed to export accounts",
+		zap.String("method", request.Method),
+		zap.String("path", request.URL.Path),
+		zap.Int("exported", e.count),
+		zap.Error(err))
+	panic(http.ErrAbortHandler)
+}
+
+// exportMediaTypes provides the media types that exports are available in.
+func exportMediaTypes() []string {
+	mediaTypes := []string{}
+	for _, rep := range exportRepresentations(false) {
+		mediaTypes = append(mediaTypes, rep.ContentType())
+	}
+	return mediaTypes
+}
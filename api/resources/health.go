@@ -0,0 +1,121 @@
+package resources
+
+import (
+	"net/http"
+
+	"github.com/freerware/negotiator/representation"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	p "github.com/freerware/tutor/api/representations/protobuf"
+	x "github.com/freerware/tutor/api/representations/xml"
+	y "github.com/freerware/tutor/api/representations/yaml"
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type HealthResourceResult struct {
+	fx.Out
+
+	HealthResource   HealthResource
+	MuxConfiguration server.MuxConfiguration `group:"muxConfigurations"`
+}
+
+type HealthResourceParameters struct {
+	fx.In
+
+	HealthService app.HealthService
+	Readiness     *server.Readiness
+	Logger        *zap.Logger
+}
+
+// HealthResource reports whether the service is alive, and whether it is
+// ready to handle requests.
+type HealthResource struct {
+	healthService app.HealthService
+	readiness     *server.Readiness
+	logger        *zap.Logger
+}
+
+func NewHealthResource(parameters HealthResourceParameters) HealthResourceResult {
+	h := HealthResource{
+		healthService: parameters.HealthService,
+		readiness:     parameters.Readiness,
+		logger:        parameters.Logger,
+	}
+	return HealthResourceResult{
+		HealthResource:   h,
+		MuxConfiguration: h.MuxConfiguration(),
+	}
+}
+
+// Liveness reports the health of each component. The service is alive as
+// long as it can respond, so unavailable components only warrant a warning.
+func (hr *HealthResource) Liveness(w http.ResponseWriter, request *http.Request) {
+	health := hr.check(request)
+	if health.Status == r.HealthFail {
+		health.Status = r.HealthWarn
+	}
+	hr.write(w, request, http.StatusOK, health)
+}
+
+// Readiness reports the health of each component, failing when any of them
+// are unavailable or when the server is stopping.
+func (hr *HealthResource) Readiness(w http.ResponseWriter, request *http.Request) {
+	health := hr.check(request)
+	check := r.HealthCheck{Component: "server", Status: r.HealthPass}
+	if !hr.readiness.Ready() {
+		check = r.HealthCheck{Component: "server", Status: r.HealthFail, Output: "the server is stopping"}
+		health.Status = r.HealthFail
+	}
+	health.Checks = append(health.Checks, check)
+
+	status := http.StatusOK
+	if health.Status == r.HealthFail {
+		status = http.StatusServiceUnavailable
+	}
+	hr.write(w, request, status, health)
+}
+
+// check checks the health of each component, failing when any of them are
+// unavailable.
+func (hr *HealthResource) check(request *http.Request) r.Health {
+	health := r.Health{Status: r.HealthPass}
+	for _, component := range hr.healthService.Check(request.Context()) {
+		check := r.HealthCheck{Component: component.Component, Status: r.HealthPass}
+		if component.Err != nil {
+			infrastructure.LoggerFrom(request.Context(), hr.logger).Warn("component is unavailable",
+				zap.String("component", component.Component),
+				zap.Error(component.Err))
+			check.Status = r.HealthFail
+			check.Output = component.Err.Error()
+			health.Status = r.HealthFail
+		}
+		health.Checks = append(health.Checks, check)
+	}
+	return health
+}
+
+// write responds with the representation of the health that best suits the
+// client. Health is never cached, since it is only meaningful when fresh.
+func (hr *HealthResource) write(
+	w http.ResponseWriter, request *http.Request, status int, health r.Health) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Add("Vary", "Accept")
+	representations := healthRepresentations(health)
+	if err := writeChosen(w, negotiable(request), status, representations...); err != nil {
+		writeError(w, request, hr.logger, err)
+	}
+}
+
+// healthRepresentations constructs the available representations of the
+// health.
+func healthRepresentations(health r.Health) []representation.Representation {
+	jrep := j.NewHealth(health)
+	yrep := y.NewHealth(health)
+	xrep := x.NewHealth(health)
+	prep := p.NewHealth(health)
+	return []representation.Representation{&jrep, &yrep, &xrep, prep}
+}
@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"net/http"
+
+	"github.com/freerware/tutor/api/openapi"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	"github.com/freerware/tutor/api/server"
+)
+
+func (hr *HealthResource) MuxConfiguration() server.MuxConfiguration {
+	health := negotiated(j.Health{}, healthRepresentations(r.Health{})...)
+	liveness := openapi.Operation{
+		Summary: "Report whether the service is alive",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Content: health},
+		},
+	}
+	readiness := openapi.Operation{
+		Summary: "Report whether the service is ready to handle requests",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Content: health},
+			{Status: http.StatusServiceUnavailable, Content: health},
+		},
+	}
+	return server.MuxConfiguration{
+		PathPrefix: "",
+		Handlers: []server.HandlerConfiguration{
+			{
+				Path:        "/healthz",
+				HandlerFunc: hr.Liveness,
+				Methods:     []string{"GET"},
+				Operation:   liveness,
+			},
+			{
+				Path:        "/readyz",
+				HandlerFunc: hr.Readiness,
+				Methods:     []string{"GET"},
+				Operation:   readiness,
+			},
+		},
+	}
+}
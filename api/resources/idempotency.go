@@ -0,0 +1,214 @@
+package resources
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/freerware/tutor/api/openapi"
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/config"
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/zap"
+)
+
+const (
+	headerIdempotencyKey      = "Idempotency-Key"
+	headerIdempotentReplayed  = "Idempotent-Replayed"
+	maximumIdempotencyKeySize = 255
+)
+
+var (
+	// errInvalidIdempotencyKey indicates that an idempotency key is too long
+	// to be recorded.
+	errInvalidIdempotencyKey = errors.New("resources: idempotency keys must be at most 255 characters")
+
+	// errIdempotencyKeyReused indicates that an idempotency key was used with
+	// a request other than the one it was first used with.
+	errIdempotencyKeyReused = errors.New("resources: idempotency key was used with a different request")
+)
+
+// idempotency replays the responses recorded for requests made with an
+// idempotency key, so that clients can safely retry unsafe requests.
+type idempotency struct {
+	service            app.IdempotencyService
+	ttl                time.Duration
+	maximumRequestSize int
+	logger             *zap.Logger
+}
+
+// newIdempotency constructs the idempotency described by the provided
+// configuration.
+func newIdempotency(
+	service app.IdempotencyService, c config.Configuration, logger *zap.Logger) idempotency {
+	return idempotency{
+		service:            service,
+		ttl:                time.Duration(c.Idempotency.TTL) * time.Second,
+		maximumRequestSize: c.Compression.MaximumRequestSize,
+		logger:             logger,
+	}
+}
+
+// wrap makes the provided handler idempotent for requests that carry an
+// idempotency key. The first request made with a key is handled, and the
+// response it declares with respond is saved along with its changes.
+// Retries are answered with the saved response instead of being handled,
+// unless the key is reused with a different request.
+func (i idempotency) wrap(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		key := request.Header.Get(headerIdempotencyKey)
+		if key == "" {
+			handler.ServeHTTP(w, request)
+			return
+		}
+		if len(key) > maximumIdempotencyKeySize {
+			writeError(w, request, i.logger, newProblem(problemTypeInvalidIdempotencyKey, errInvalidIdempotencyKey))
+			return
+		}
+
+		// identify the request by its target and the body as it was sent.
+		fingerprint, err := i.fingerprint(request)
+		if err != nil {
+			writeError(w, request, i.logger, newProblem(problemTypeMalformedBody, err))
+			return
+		}
+
+		// replay the response to the request the key was first used with.
+		record, err := i.service.Get(key)
+		if err != nil {
+			writeError(w, request, i.logger, err)
+			return
+		}
+		if record != nil {
+			if record.Fingerprint != fingerprint {
+				writeError(w, request, i.logger, newProblem(problemTypeIdempotencyKeyReused, errIdempotencyKeyReused))
+				return
+			}
+			for name, values := range record.Header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(headerIdempotentReplayed, "true")
+			w.WriteHeader(record.Status)
+			w.Write(record.Body)
+			return
+		}
+
+		now := time.Now()
+		ctx := app.WithIdempotencyRecord(request.Context(), &infrastructure.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(i.ttl),
+		})
+		handler.ServeHTTP(w, request.WithContext(ctx))
+	})
+}
+
+// middleware provides the idempotency as a middleware of the handlers that
+// declare their responses.
+func (i idempotency) middleware() server.Middleware {
+	return server.Middleware{Name: "idempotency", Wrap: i.wrap}
+}
+
+// fingerprint digests the principal, method, target, and body of the
+// request, leaving the body intact for the handler. Including the principal
+// prevents a key from replaying a response to another principal. Bodies
+// beyond the maximum request size are only partially digested, since the
+// handler rejects them.
+func (i idempotency) fingerprint(request *http.Request) (string, error) {
+	var reader io.Reader = request.Body
+	if i.maximumRequestSize > 0 {
+		reader = io.LimitReader(request.Body, int64(i.maximumRequestSize)+1)
+	}
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(b), request.Body))
+
+	var principal string
+	if p, ok := app.PrincipalFrom(request.Context()); ok {
+		principal = p.AccountUUID.String()
+	}
+	h := sha256.New()
+	for _, s := range []string{
+		principal,
+		request.Method,
+		request.URL.RequestURI(),
+		request.Header.Get("Content-Type"),
+		request.Header.Get("Content-Encoding"),
+	} {
+		io.WriteString(h, s)
+		h.Write([]byte{0})
+	}
+	h.Write(b)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// respond declares the response that the request is answered with once its
+// changes are saved, so that the response is saved along with them when the
+// request carries an idempotency key.
+func respond(request *http.Request, status int, header http.Header) {
+	respondWith(request, status, header, []byte{})
+}
+
+// respondWith declares the response like respond, for responses that carry
+// a body.
+func respondWith(request *http.Request, status int, header http.Header, body []byte) {
+	record, ok := app.IdempotencyRecordFrom(request.Context())
+	if !ok {
+		return
+	}
+	record.Status = status
+	record.Header = map[string][]string{}
+	for name, values := range header {
+		record.Header[name] = values
+	}
+	record.Body = body
+}
+
+// recorder buffers a response, so that it can be declared before it is
+// written.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *recorder) Header() http.Header { return r.header }
+
+func (r *recorder) WriteHeader(status int) { r.status = status }
+
+func (r *recorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+
+// writeTo writes the buffered response.
+func (r *recorder) writeTo(w http.ResponseWriter) {
+	for name, values := range r.header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body.Bytes())
+}
+
+// idempotent describes the idempotency key accepted by the provided
+// operation, along with the problems it can cause.
+func idempotent(o openapi.Operation) openapi.Operation {
+	o.Parameters = append(append([]openapi.Parameter{}, o.Parameters...), openapi.Parameter{
+		Name:        headerIdempotencyKey,
+		In:          "header",
+		Description: "Identifies the request across retries, which are answered with the original response.",
+		Schema:      "",
+	})
+	o.Responses = append(append([]openapi.Response{}, o.Responses...),
+		problems(http.StatusConflict, http.StatusUnprocessableEntity)...)
+	return o
+}
@@ -0,0 +1,334 @@
+package resources
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	c "github.com/freerware/tutor/api/representations/csv"
+	j "github.com/freerware/tutor/api/representations/json"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+)
+
+const queryParameterAtomic = "atomic"
+
+// importRecord is a record of a bulk import, along with the reason it could
+// not be decoded, if it could not be.
+type importRecord struct {
+	account j.Account
+	err     error
+}
+
+// importDecoders decodes the records of a bulk import according to the
+// media type of the request body.
+var importDecoders = map[string]func([]byte) ([]importRecord, error){
+	"application/json":     jsonRecords,
+	"application/x-ndjson": ndjsonRecords,
+	"text/csv":             csvRecords,
+}
+
+// jsonRecords decodes a JSON array of accounts.
+func jsonRecords(b []byte) ([]importRecord, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(b, &elements); err != nil {
+		return nil, err
+	}
+	records := make([]importRecord, len(elements))
+	for i, element := range elements {
+		records[i] = jsonRecord(element)
+	}
+	return records, nil
+}
+
+// ndjsonRecords decodes newline delimited JSON accounts, one per line.
+func ndjsonRecords(b []byte) ([]importRecord, error) {
+	records := []importRecord{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(nil, len(b)+1)
+	for scanner.Scan() {
+		if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+			records = append(records, jsonRecord(line))
+		}
+	}
+	return records, scanner.Err()
+}
+
+func jsonRecord(b []byte) importRecord {
+	var account j.Account
+	err := json.Unmarshal(b, &account)
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		return importRecord{err: malformedField(typeErr.Field)}
+	case err != nil:
+		return importRecord{err: i18n.Errorf("resources.malformedRecord", "resources: record is malformed")}
+	}
+	return importRecord{account: account}
+}
+
+// csvRecords decodes accounts from comma separated values, in the columns
+// that accounts are exported in. A header row names the columns present,
+// and consecutive rows for the same account each contribute one post.
+func csvRecords(b []byte) ([]importRecord, error) {
+	reader := csv.NewReader(bytes.NewReader(b))
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []importRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range header {
+		if !slices.Contains(c.AccountColumns, column) && !slices.Contains(c.PostColumns, column) {
+			return nil, i18n.Errorf(
+				"resources.unknownColumn", "resources: column %s is not recognized", column)
+		}
+	}
+
+	records := []importRecord{}
+	previous := ""
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		values := map[string]string{}
+		for i, column := range header {
+			values[column] = row[i]
+		}
+
+		// rows continuing the previous account only contribute a post.
+		key := values["uuid"]
+		if key == "" {
+			key = values["primaryCredential"]
+		}
+		continued := key != "" && key == previous && len(records) > 0
+		previous = key
+		if !continued {
+			account, err := csvAccount(values)
+			records = append(records, importRecord{account: account, err: err})
+		}
+		last := &records[len(records)-1]
+		if last.err != nil {
+			continue
+		}
+		post, ok, err := csvPost(values)
+		if err != nil {
+			last.err = err
+			continue
+		}
+		if ok {
+			last.account.Posts = append(last.account.Posts, post)
+		}
+	}
+}
+
+func csvAccount(values map[string]string) (j.Account, error) {
+	account := j.Account{
+		PrimaryCredential: values["primaryCredential"],
+		GivenName:         values["givenName"],
+		Surname:           values["surname"],
+		Posts:             []j.Post{},
+	}
+	var err error
+	if account.UUID, err = csvUUID(values, "uuid"); err != nil {
+		return account, err
+	}
+	if account.CreatedAt, err = csvTime(values, "createdAt"); err != nil {
+		return account, err
+	}
+	if account.UpdatedAt, err = csvTime(values, "updatedAt"); err != nil {
+		return account, err
+	}
+	account.DeletedAt, err = csvOptionalTime(values, "deletedAt")
+	return account, err
+}
+
+// csvPost decodes the post within a row, indicating if there is one.
+func csvPost(values map[string]string) (j.Post, bool, error) {
+	present := false
+	for _, column := range c.PostColumns {
+		present = present || values[column] != ""
+	}
+	if !present {
+		return j.Post{}, false, nil
+	}
+	post := j.Post{Title: values["postTitle"], Content: values["postContent"]}
+	var err error
+	if post.UUID, err = csvUUID(values, "postUUID"); err != nil {
+		return post, true, err
+	}
+	if s := values["postIsDraft"]; s != "" {
+		if post.Draft, err = strconv.ParseBool(s); err != nil {
+			return post, true, malformedField("postIsDraft")
+		}
+	}
+	if s := values["postLikes"]; s != "" {
+		if post.Likes, err = strconv.Atoi(s); err != nil {
+			return post, true, malformedField("postLikes")
+		}
+	}
+	if post.CreatedAt, err = csvTime(values, "postCreatedAt"); err != nil {
+		return post, true, err
+	}
+	if post.UpdatedAt, err = csvTime(values, "postUpdatedAt"); err != nil {
+		return post, true, err
+	}
+	post.DeletedAt, err = csvOptionalTime(values, "postDeletedAt")
+	return post, true, err
+}
+
+func csvUUID(values map[string]string, column string) (u.UUID, error) {
+	if values[column] == "" {
+		return u.Nil, nil
+	}
+	uuid, err := u.FromString(values[column])
+	if err != nil {
+		return u.Nil, malformedField(column)
+	}
+	return uuid, nil
+}
+
+func csvTime(values map[string]string, column string) (time.Time, error) {
+	t, err := csvOptionalTime(values, column)
+	if err != nil || t == nil {
+		return time.Time{}, err
+	}
+	return *t, nil
+}
+
+func csvOptionalTime(values map[string]string, column string) (*time.Time, error) {
+	if values[column] == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, values[column])
+	if err != nil {
+		return nil, malformedField(column)
+	}
+	return &t, nil
+}
+
+func malformedField(field string) error {
+	return i18n.Errorf("resources.malformedField", "resources: field %s is malformed", field)
+}
+
+// importedAccount constructs the account described by an import record.
+// Unlike accounts created individually, imported accounts retain their
+// identity and timestamps when they are provided.
+func importedAccount(rep j.Account, now time.Time) (domain.Account, error) {
+	accountUUID := rep.UUID
+	if accountUUID == u.Nil {
+		accountUUID = u.Must(u.NewV4())
+	}
+	posts := []domain.Post{}
+	for _, post := range rep.Posts {
+		postUUID := post.UUID
+		if postUUID == u.Nil {
+			postUUID = u.Must(u.NewV4())
+		}
+		createdAt, updatedAt := importedTimes(post.CreatedAt, post.UpdatedAt, now)
+		p, err := domain.NewPost(domain.PostParameters{
+			UUID:       postUUID,
+			Title:      post.Title,
+			Content:    post.Content,
+			Draft:      post.Draft,
+			Likes:      post.Likes,
+			AuthorUUID: accountUUID,
+			CreatedAt:  createdAt,
+			UpdatedAt:  updatedAt,
+			DeletedAt:  post.DeletedAt,
+		})
+		if err != nil {
+			return domain.Account{}, err
+		}
+		posts = append(posts, p)
+	}
+	createdAt, updatedAt := importedTimes(rep.CreatedAt, rep.UpdatedAt, now)
+	return domain.NewAccount(domain.AccountParameters{
+		UUID:      accountUUID,
+		GivenName: rep.GivenName,
+		Surname:   rep.Surname,
+		Username:  rep.PrimaryCredential,
+		Posts:     posts,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		DeletedAt: rep.DeletedAt,
+	})
+}
+
+// importedTimes provides the creation and modification times of an imported
+// record, which default to the time of the import.
+func importedTimes(createdAt, updatedAt, now time.Time) (time.Time, time.Time) {
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	if updatedAt.IsZero() {
+		updatedAt = createdAt
+	}
+	return createdAt, updatedAt
+}
+
+// importReport reports the outcome of each record, keeping why records were
+// skipped or failed so that the reasons can be explained in any locale.
+type importReport struct {
+	r.ImportReport
+
+	errs []error
+}
+
+func newImportReport(records int) *importReport {
+	return &importReport{
+		ImportReport: r.ImportReport{Records: make([]r.ImportRecord, records)},
+		errs:         make([]error, records),
+	}
+}
+
+// record reports the outcome of the record at the provided index.
+func (ir *importReport) record(index int, uuid u.UUID, outcome string, err error) {
+	record := r.ImportRecord{Index: index, Outcome: outcome}
+	if uuid != u.Nil {
+		record.UUID = uuid.String()
+	}
+	ir.errs[index] = err
+	switch outcome {
+	case app.ImportCreated:
+		ir.Created++
+	case app.ImportSkipped:
+		ir.Skipped++
+	default:
+		ir.Failed++
+	}
+	ir.Records[index] = record
+}
+
+// in provides the report explaining why records were skipped or failed in
+// the provided locale. Reasons that the catalog cannot explain are not shared
+// with clients.
+func (ir *importReport) in(locale string) r.ImportReport {
+	report := ir.ImportReport
+	report.Records = append([]r.ImportRecord(nil), ir.Records...)
+	for i, err := range ir.errs {
+		if err == nil {
+			continue
+		}
+		reason, ok := localized(locale, err)
+		if !ok {
+			reason = i18n.Default.Message(locale, "problem.internal")
+		}
+		report.Records[i].Reason = reason
+	}
+	return report
+}
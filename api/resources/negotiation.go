@@ -0,0 +1,386 @@
+package resources
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/freerware/negotiator"
+	"github.com/freerware/negotiator/proactive"
+	"github.com/freerware/negotiator/reactive"
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/negotiator/transparent"
+	"github.com/freerware/tutor/api/encoding"
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	p "github.com/freerware/tutor/api/representations/protobuf"
+	x "github.com/freerware/tutor/api/representations/xml"
+	y "github.com/freerware/tutor/api/representations/yaml"
+	"github.com/freerware/tutor/config"
+	"github.com/gorilla/mux"
+)
+
+// The strategies that routes can negotiate their representations with.
+const (
+	strategyProactive   = "proactive"
+	strategyReactive    = "reactive"
+	strategyTransparent = "transparent"
+)
+
+// variantExtensions associates the media type of each representation with
+// the extension of the variant resource that serves it alone.
+var variantExtensions = map[string]string{
+	"application/json":     "json",
+	"application/xml":      "xml",
+	"application/yaml":     "yaml",
+	"application/protobuf": "pb",
+}
+
+// variantFormat matches the extension of a variant resource.
+const variantFormat = "{format:json|xml|yaml|pb}"
+
+// negotiation negotiates the representation served by each route using the
+// strategy configured for it.
+type negotiation struct {
+	strategy    string
+	routes      map[string]string
+	compression compression
+}
+
+// newNegotiation constructs the negotiation described by the provided
+// configuration.
+func newNegotiation(c config.Configuration) (negotiation, error) {
+	n := negotiation{
+		strategy:    strategyProactive,
+		routes:      map[string]string{},
+		compression: newCompression(c.Compression),
+	}
+	if c.Negotiation.Strategy != "" {
+		n.strategy = c.Negotiation.Strategy
+	}
+	if err := validStrategy(n.strategy); err != nil {
+		return negotiation{}, err
+	}
+	for route, strategy := range c.Negotiation.Routes {
+		if strategy == "" {
+			continue
+		}
+		if err := validStrategy(strategy); err != nil {
+			return negotiation{}, fmt.Errorf("%w for route %s", err, route)
+		}
+		n.routes[strings.TrimSuffix(route, "/")] = strategy
+	}
+	return n, nil
+}
+
+func validStrategy(strategy string) error {
+	switch strategy {
+	case strategyProactive, strategyReactive, strategyTransparent:
+		return nil
+	}
+	return fmt.Errorf("resources: unknown negotiation strategy %q", strategy)
+}
+
+// strategyFor determines the strategy of the route that matched the request.
+func (n negotiation) strategyFor(request *http.Request) string {
+	if route := mux.CurrentRoute(request); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			if strategy, ok := n.routes[strings.TrimSuffix(template, "/")]; ok {
+				return strategy
+			}
+		}
+	}
+	return n.strategy
+}
+
+// selected determines the representation that will be served in response to
+// the request, if the server is the one to choose it.
+func (n negotiation) selected(
+	request *http.Request,
+	reps ...representation.Representation) (representation.Representation, error) {
+	if rep, ok := variant(request, reps...); ok {
+		return encodedAs(request, rep, reps...), nil
+	}
+	if n.strategyFor(request) != strategyProactive {
+		return nil, nil
+	}
+	rep, err := choose(negotiable(request), identities(reps...)...)
+	if err != nil || rep == nil {
+		return nil, err
+	}
+	return encodedAs(request, rep, reps...), nil
+}
+
+// choose determines the representation that best suits the request. The
+// chooser breaks ties arbitrarily when offered many representations, such
+// as those of every locale, so once it settles the language the
+// representation is chosen again amongst those in that language alone.
+func choose(
+	request *http.Request,
+	reps ...representation.Representation) (representation.Representation, error) {
+	chosen, err := chooser.Choose(request, reps...)
+	if err != nil || chosen == nil {
+		return chosen, err
+	}
+	localized := []representation.Representation{}
+	for _, rep := range reps {
+		if rep.ContentLanguage() == chosen.ContentLanguage() {
+			localized = append(localized, rep)
+		}
+	}
+	if len(localized) == len(reps) {
+		return chosen, nil
+	}
+	return chooser.Choose(request, localized...)
+}
+
+// negotiate responds to the request with the representations of the
+// resource. Requests for a variant resource are served its representation
+// as is; otherwise, the strategy configured for the route is used. In either
+// case, the representation that is served, be it the chosen representation
+// or the list of alternatives, is compressed with the content coding that
+// the client prefers.
+func (n negotiation) negotiate(
+	w http.ResponseWriter,
+	request *http.Request,
+	reps ...representation.Representation) error {
+	if rep, ok := variant(request, reps...); ok {
+		w.Header().Add("Vary", "Accept-Encoding")
+		return writeVariant(w, encodedAs(request, rep, reps...))
+	}
+	w.Header().Add("Vary", "Accept, Accept-Encoding")
+	ctx := negotiator.NegotiationContext{Request: negotiable(request), ResponseWriter: w}
+	identity := identities(reps...)
+	switch n.strategyFor(request) {
+	case strategyReactive:
+		return reactive.New(
+			reactive.Representation(n.compressed(request, alternatives(ctx.Request))),
+		).Negotiate(ctx, identity...)
+	case strategyTransparent:
+		return transparent.New(
+			transparent.RVSA(encodingChooser{Chooser: transparent.RVSA1(), request: request, reps: reps}),
+			transparent.ListRepresentation(n.compressed(request, alternatives(ctx.Request))),
+		).Negotiate(ctx, identity...)
+	}
+	rep, err := n.selected(request, reps...)
+	if err != nil {
+		return err
+	}
+	if rep == nil {
+		return proactive.Default.Negotiate(ctx, identity...)
+	}
+	return proactive.Default.Negotiate(ctx, rep)
+}
+
+// compressed compresses the lists of alternatives that the provided
+// constructor constructs with the content coding that the client prefers.
+func (n negotiation) compressed(
+	request *http.Request, construct representation.ListConstructor) representation.ListConstructor {
+	return func(reps ...representation.Representation) representation.Representation {
+		list := construct(reps...)
+		compressed, err := n.compression.compress(list)
+		if err != nil {
+			return list
+		}
+		return encodedAs(request, list, compressed...)
+	}
+}
+
+// encodingChooser chooses representations with the provided chooser,
+// compressing the chosen representation with the content coding that the
+// client prefers.
+type encodingChooser struct {
+	representation.Chooser
+
+	request *http.Request
+	reps    []representation.Representation
+}
+
+func (c encodingChooser) Choose(
+	request *http.Request, reps ...representation.Representation) (representation.Representation, error) {
+	rep, err := c.Chooser.Choose(request, reps...)
+	if err != nil || rep == nil {
+		return rep, err
+	}
+	return encodedAs(c.request, rep, c.reps...), nil
+}
+
+// negotiable provides the request that the negotiator operates on. Content
+// codings are chosen separately, since the negotiator only understands a
+// handful of them.
+func negotiable(request *http.Request) *http.Request {
+	r := request.Clone(request.Context())
+	r.Header.Del("Accept-Encoding")
+	return r
+}
+
+// localize offers the representations constructed in each supported locale,
+// leaving the negotiator to choose amongst the languages along with
+// everything else. Clients without an acceptable language are offered the
+// default locale alone, disregarding their language preferences, so the
+// request that the representations are negotiated for is provided as well.
+func localize(
+	request *http.Request,
+	localized func(locale string) []representation.Representation) (*http.Request, []representation.Representation) {
+	locales := i18n.Default.Locales()
+	if _, ok := i18n.Default.Match(request.Header.Values("Accept-Language")...); !ok {
+		r := request.Clone(request.Context())
+		r.Header.Del("Accept-Language")
+		return r, localized(locales[0])
+	}
+	reps := []representation.Representation{}
+	for _, locale := range locales {
+		reps = append(reps, localized(locale)...)
+	}
+	return request, reps
+}
+
+// identities provides the representations without a content coding applied.
+func identities(reps ...representation.Representation) []representation.Representation {
+	identity := []representation.Representation{}
+	for _, rep := range reps {
+		if isIdentity(rep) {
+			identity = append(identity, rep)
+		}
+	}
+	return identity
+}
+
+// isIdentity indicates if the representation has no content coding applied.
+func isIdentity(rep representation.Representation) bool {
+	coding := rep.ContentEncoding()
+	return len(coding) == 0 || (len(coding) == 1 && coding[0] == encoding.Identity)
+}
+
+// encodedAs provides the counterpart of the representation compressed with
+// the content coding that the client prefers, if it is available.
+func encodedAs(
+	request *http.Request,
+	rep representation.Representation,
+	reps ...representation.Representation) representation.Representation {
+	available := map[string]representation.Representation{}
+	codings := []string{}
+	for _, r := range reps {
+		e, ok := r.(*encoded)
+		if !ok || !sameVariant(e, rep) {
+			continue
+		}
+		available[e.coding] = e
+		codings = append(codings, e.coding)
+	}
+	coding := encoding.Preferred(request.Header.Values("Accept-Encoding"), codings...)
+	if e, ok := available[coding]; ok {
+		return e
+	}
+	return rep
+}
+
+// sameVariant indicates if the representations only differ by content coding.
+func sameVariant(one, two representation.Representation) bool {
+	return one.ContentType() == two.ContentType() &&
+		one.ContentLanguage() == two.ContentLanguage() &&
+		one.ContentCharset() == two.ContentCharset()
+}
+
+// alternatives constructs the list of alternatives in the format that best
+// suits the client, falling back to JSON when none are acceptable.
+func alternatives(request *http.Request) representation.ListConstructor {
+	return func(reps ...representation.Representation) representation.Representation {
+		lists := alternativesRepresentations(*request.URL, r.NewAlternatives(reps...)...)
+		chosen, err := chooser.Choose(request, lists...)
+		if err != nil || chosen == nil {
+			return lists[0]
+		}
+		return chosen
+	}
+}
+
+// alternativesRepresentations constructs the available representations of
+// the list of alternatives, located at the provided location.
+func alternativesRepresentations(
+	location url.URL, alts ...r.Alternative) []representation.Representation {
+	jalts := j.NewAlternatives(alts...)
+	jalts.SetContentLocation(location)
+	xalts := x.NewAlternatives(alts...)
+	xalts.SetContentLocation(location)
+	yalts := y.NewAlternatives(alts...)
+	yalts.SetContentLocation(location)
+	palts := p.NewAlternatives(alts...)
+	palts.SetContentLocation(location)
+	return []representation.Representation{&jalts, &xalts, &yalts, palts}
+}
+
+// locatable is a representation whose location can be assigned.
+type locatable interface {
+	representation.Representation
+	SetContentLocation(url.URL)
+}
+
+// located locates each of the representations at the variant resource that
+// serves it alone.
+func located(location url.URL, reps ...locatable) []representation.Representation {
+	located := make([]representation.Representation, len(reps))
+	for i, rep := range reps {
+		rep.SetContentLocation(variantLocation(location, rep.ContentType()))
+		located[i] = rep
+	}
+	return located
+}
+
+// resourceLocation provides the location of the negotiable resource that
+// the request is for, even when it is for one of its variants.
+func resourceLocation(request *http.Request) url.URL {
+	location := *request.URL
+	if format, ok := mux.Vars(request)["format"]; ok {
+		location.Path = strings.TrimSuffix(location.Path, "."+format)
+	}
+	return location
+}
+
+// variantLocation provides the location of the variant resource that serves
+// the representation of the provided media type alone.
+func variantLocation(location url.URL, mediaType string) url.URL {
+	if extension, ok := variantExtensions[mediaType]; ok {
+		location.Path = strings.TrimSuffix(location.Path, "/") + "." + extension
+	}
+	return location
+}
+
+// variant selects the representation served by the variant resource that the
+// request is for, if it is for one.
+func variant(
+	request *http.Request,
+	reps ...representation.Representation) (representation.Representation, bool) {
+	format, ok := mux.Vars(request)["format"]
+	if !ok {
+		return nil, false
+	}
+	for _, rep := range reps {
+		if variantExtensions[rep.ContentType()] == format && isIdentity(rep) {
+			return rep, true
+		}
+	}
+	return nil, false
+}
+
+// writeVariant responds with the provided representation.
+func writeVariant(w http.ResponseWriter, rep representation.Representation) error {
+	b, err := rep.Bytes()
+	if err != nil {
+		return err
+	}
+	location := rep.ContentLocation()
+	if !isIdentity(rep) {
+		w.Header().Set("Content-Encoding", strings.Join(rep.ContentEncoding(), ", "))
+	}
+	w.Header().Set("Content-Type", rep.ContentType())
+	w.Header().Set("Content-Language", rep.ContentLanguage())
+	w.Header().Set("Content-Location", location.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,60 @@
+package resources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/freerware/tutor/api/encoding"
+	"github.com/freerware/tutor/api/i18n"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	x "github.com/freerware/tutor/api/representations/xml"
+)
+
+func TestNegotiateCompressesWhatIsServed(t *testing.T) {
+	tests := []struct {
+		strategy  string
+		negotiate string
+		status    int
+	}{
+		{strategy: strategyProactive, status: http.StatusOK},
+		{strategy: strategyReactive, status: http.StatusMultipleChoices},
+		{strategy: strategyTransparent, status: http.StatusMultipleChoices},
+		{strategy: strategyTransparent, negotiate: "*", status: http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.strategy+" "+test.negotiate, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "http://example.com/tokens", nil)
+			request.Header.Set("Accept", "application/json")
+			request.Header.Set("Accept-Charset", "utf-8")
+			request.Header.Set("Accept-Language", i18n.DefaultLocale)
+			request.Header.Set("Accept-Encoding", encoding.Gzip)
+			if test.negotiate != "" {
+				request.Header.Set("Negotiate", test.negotiate)
+			}
+			token := r.Token{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 3600}
+			jtoken, xtoken := j.NewToken(token), x.NewToken(token)
+			n := negotiation{strategy: test.strategy, compression: compression{}}
+			reps, err := n.compression.compress(located(*request.URL, &jtoken, &xtoken)...)
+			if err != nil {
+				t.Fatalf("failed to compress: %v", err)
+			}
+			w := httptest.NewRecorder()
+
+			if err := n.negotiate(w, request, reps...); err != nil {
+				t.Fatalf("failed to negotiate: %v", err)
+			}
+
+			if w.Code != test.status {
+				t.Fatalf("expected status %d, got %d", test.status, w.Code)
+			}
+			if coding := w.Header().Get("Content-Encoding"); coding != encoding.Gzip {
+				t.Fatalf("expected the %s content coding, got %q", encoding.Gzip, coding)
+			}
+			if _, err := encoding.NewReader(w.Body, encoding.Gzip); err != nil {
+				t.Fatalf("expected a %s body: %v", encoding.Gzip, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
+	"github.com/freerware/tutor/api/openapi"
+	"github.com/freerware/tutor/api/patch"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+)
+
+// The parameters shared by several operations.
+var (
+	pageParameters = []openapi.Parameter{
+		{
+			Name:        queryParameterPageSize,
+			In:          "query",
+			Description: "The maximum number of items within the page.",
+			Schema:      0,
+		},
+		{
+			Name:        queryParameterPageToken,
+			In:          "query",
+			Description: "The opaque token identifying the page.",
+			Schema:      "",
+		},
+	}
+	conditionalParameters = []openapi.Parameter{
+		{Name: "If-Match", In: "header", Schema: ""},
+		{Name: "If-None-Match", In: "header", Schema: ""},
+		{Name: "If-Modified-Since", In: "header", Schema: ""},
+		{Name: "If-Unmodified-Since", In: "header", Schema: ""},
+	}
+)
+
+// negotiated describes a body that is negotiated amongst the provided
+// representations, which are those that the handler constructs.
+func negotiated(schema any, reps ...representation.Representation) *openapi.Content {
+	return &openapi.Content{MediaTypes: mediaTypes(reps...), Schema: schema}
+}
+
+// mediaTypes provides the distinct media types of the provided
+// representations, in the order they are first encountered.
+func mediaTypes(reps ...representation.Representation) []string {
+	seen := map[string]bool{}
+	mediaTypes := []string{}
+	for _, rep := range reps {
+		if !seen[rep.ContentType()] {
+			seen[rep.ContentType()] = true
+			mediaTypes = append(mediaTypes, rep.ContentType())
+		}
+	}
+	return mediaTypes
+}
+
+// patchDocument describes the patch documents accepted by PATCH requests.
+func patchDocument() *openapi.Content {
+	return &openapi.Content{MediaTypes: patch.MediaTypes()}
+}
+
+// problems describes the problem details responded with for each of the
+// provided status codes.
+func problems(statuses ...int) []openapi.Response {
+	reps := problemRepresentations(r.Problem{}, i18n.DefaultLocale)
+	responses := make([]openapi.Response, len(statuses))
+	for i, status := range statuses {
+		responses[i] = openapi.Response{
+			Status: status,
+			Content: &openapi.Content{
+				MediaTypes: mediaTypes(reps...),
+				Schema:     j.Error{},
+			},
+		}
+	}
+	return responses
+}
+
+// responses combines the provided responses.
+func responses(success []openapi.Response, failures ...int) []openapi.Response {
+	return append(success, problems(append(failures, http.StatusInternalServerError)...)...)
+}
+
+// multipleChoices describes the list of alternatives responded with by
+// routes that negotiate reactively or transparently.
+func multipleChoices() openapi.Response {
+	return openapi.Response{
+		Status:  http.StatusMultipleChoices,
+		Headers: []string{"Alternates", "TCN"},
+		Content: negotiated(j.Alternatives{}, alternativesRepresentations(url.URL{})...),
+	}
+}
+
+// variantOperation describes the variant resources of the negotiable
+// resource described by the provided operation. Each variant resource serves
+// a single representation, so it is never negotiated.
+func variantOperation(summary string, o openapi.Operation) openapi.Operation {
+	o.Summary = summary
+	responses := []openapi.Response{}
+	for _, response := range o.Responses {
+		switch response.Status {
+		case http.StatusMultipleChoices, http.StatusNotAcceptable:
+			continue
+		}
+		responses = append(responses, response)
+	}
+	o.Responses = responses
+	return o
+}
@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+
+	r "github.com/freerware/tutor/api/representations"
+)
+
+const (
+	queryParameterPageSize  = "pageSize"
+	queryParameterPageToken = "pageToken"
+)
+
+// Errors that are potentially thrown while interpreting pagination parameters.
+var (
+	ErrInvalidPageSize  = errors.New("resources: page size must be a positive integer")
+	ErrInvalidPageToken = r.ErrInvalidPageToken
+)
+
+// pageRequest represents the slice of a collection requested by a client.
+type pageRequest struct {
+	offset int
+	size   int
+}
+
+// newPageRequest interprets the pagination query parameters of the provided URL.
+func newPageRequest(u *url.URL) (pageRequest, error) {
+	query := u.Query()
+	pr := pageRequest{size: r.DefaultPageSize}
+	if s := query.Get(queryParameterPageSize); s != "" {
+		size, err := strconv.Atoi(s)
+		if err != nil || size < 1 {
+			return pageRequest{}, ErrInvalidPageSize
+		}
+		pr.size = min(size, r.MaximumPageSize)
+	}
+	if t := query.Get(queryParameterPageToken); t != "" {
+		offset, err := r.DecodePageToken(t)
+		if err != nil {
+			return pageRequest{}, err
+		}
+		pr.offset = offset
+	}
+	return pr, nil
+}
+
+// page describes the page served for the request, given the number of
+// items that were retrieved and the total size of the collection.
+func (pr pageRequest) page(u url.URL, count, total int) r.Page {
+	page := r.Page{Size: pr.size, TotalCount: total, Self: u}
+	if pr.offset+count < total {
+		token := r.EncodePageToken(pr.offset + pr.size)
+		next := pageURL(u, pr.size, token)
+		page.Next = &next
+		page.NextPageToken = token
+	}
+	if pr.offset > 0 {
+		token := r.EncodePageToken(max(pr.offset-pr.size, 0))
+		previous := pageURL(u, pr.size, token)
+		page.Previous = &previous
+		page.PreviousPageToken = token
+	}
+	return page
+}
+
+// pageURL constructs the URL of the page with the provided size and token.
+func pageURL(u url.URL, size int, token string) url.URL {
+	query := u.Query()
+	query.Set(queryParameterPageSize, strconv.Itoa(size))
+	query.Set(queryParameterPageToken, token)
+	u.RawQuery = query.Encode()
+	return u
+}
@@ -0,0 +1,76 @@
+package resources
+
+import (
+	"net/http"
+
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/config"
+	u "github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type PasswordResourceResult struct {
+	fx.Out
+
+	PasswordResource PasswordResource
+	MuxConfiguration server.MuxConfiguration `group:"muxConfigurations"`
+}
+
+type PasswordResourceParameters struct {
+	fx.In
+
+	AuthenticationService app.AuthenticationService
+	Configuration         config.Configuration
+	Logger                *zap.Logger
+}
+
+// PasswordResource is the password that an account authenticates with,
+// which can be replaced but never retrieved.
+type PasswordResource struct {
+	authenticationService app.AuthenticationService
+	compression           compression
+	logger                *zap.Logger
+}
+
+func NewPasswordResource(parameters PasswordResourceParameters) PasswordResourceResult {
+	pr := PasswordResource{
+		authenticationService: parameters.AuthenticationService,
+		compression:           newCompression(parameters.Configuration.Compression),
+		logger:                parameters.Logger,
+	}
+	return PasswordResourceResult{
+		PasswordResource: pr,
+		MuxConfiguration: pr.MuxConfiguration(),
+	}
+}
+
+func (pr *PasswordResource) Replace(w http.ResponseWriter, request *http.Request) {
+
+	// retrieve the account uuid.
+	vars := mux.Vars(request)
+	uuid, err := u.FromString(vars["uuid"])
+	if err != nil {
+		writeError(w, request, pr.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
+
+	// decode the request body.
+	change, err := readPasswordChange(w, request, pr.compression)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	// change the password.
+	err = pr.authenticationService.ChangePassword(
+		request.Context(), uuid, change.CurrentPassword, change.Password)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
@@ -0,0 +1,43 @@
+package resources
+
+import (
+	"net/http"
+
+	"github.com/freerware/tutor/api/openapi"
+	j "github.com/freerware/tutor/api/representations/json"
+	"github.com/freerware/tutor/api/server"
+)
+
+func (pr *PasswordResource) MuxConfiguration() server.MuxConfiguration {
+	replace := secured(openapi.Operation{
+		Summary:     "Change the password of an existing account",
+		RequestBody: &openapi.Content{MediaTypes: keys(passwordChangeDecoders), Schema: j.PasswordChange{}},
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusNoContent},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge,
+			http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity),
+	})
+
+	// only authenticated clients can change passwords.
+	protected := []server.Middleware{authenticated(pr.logger)}
+
+	return server.MuxConfiguration{
+		PathPrefix: "/accounts/{uuid}/password",
+		Handlers: []server.HandlerConfiguration{
+			{
+				Path:        "",
+				HandlerFunc: pr.Replace,
+				Middlewares: protected,
+				Methods:     []string{"PUT"},
+				Operation:   replace,
+			},
+			{
+				Path:        "/",
+				HandlerFunc: pr.Replace,
+				Middlewares: protected,
+				Methods:     []string{"PUT"},
+				Operation:   replace,
+			},
+		},
+	}
+}
@@ -0,0 +1,138 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/freerware/tutor/api/i18n"
+	"github.com/freerware/tutor/api/patch"
+	j "github.com/freerware/tutor/api/representations/json"
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+)
+
+// readPatch retrieves the media type and decompressed contents of the patch
+// document within the request, advertising the supported patch document
+// formats when the media type is not one of them.
+func readPatch(
+	w http.ResponseWriter, request *http.Request, c compression) (string, []byte, error) {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil || (mediaType != patch.MediaTypeMergePatch && mediaType != patch.MediaTypeJSONPatch) {
+		w.Header().Set("Accept-Patch", strings.Join(patch.MediaTypes(), ", "))
+		return "", nil, newProblem(problemTypeUnsupportedMediaType, i18n.Errorf(
+			"resources.unsupportedPatch",
+			"resources: patch documents must be one of %s",
+			strings.Join(patch.MediaTypes(), ", ")))
+	}
+	document, err := c.read(w, request)
+	if err != nil {
+		return "", nil, err
+	}
+	return mediaType, document, nil
+}
+
+// applyPatch applies the patch document to the representation, and
+// decodes the patched document into the provided destination.
+func applyPatch(
+	rep interface{ Bytes() ([]byte, error) }, mediaType string, document []byte, dest any) error {
+	b, err := rep.Bytes()
+	if err != nil {
+		return err
+	}
+	patched, err := patch.Apply(mediaType, b, document)
+	switch {
+	case errors.Is(err, patch.ErrMalformedPatch):
+		return newProblem(problemTypeMalformedPatch, err)
+	case errors.Is(err, patch.ErrTestFailed):
+		return newProblem(problemTypePatchConflict, err)
+	case errors.Is(err, patch.ErrPathNotFound):
+		return newProblem(problemTypeUnprocessablePatch, err)
+	case err != nil:
+		return err
+	}
+	if err = json.Unmarshal(patched, dest); err != nil {
+		return newProblem(problemTypeUnprocessablePatch, err)
+	}
+	return nil
+}
+
+// applyAccount applies the state described by the representation to the
+// account through its setters, so that the domain validation is enforced.
+// Server-managed members, such as timestamps, are not modifiable.
+func applyAccount(account *domain.Account, rep j.Account, now time.Time) error {
+	if rep.UUID != account.UUID() {
+		return mismatchedUUIDProblem()
+	}
+	account.SetGivenName(rep.GivenName)
+	account.SetSurname(rep.Surname)
+	account.SetUsername(rep.PrimaryCredential)
+
+	posts := []domain.Post{}
+	for _, postRep := range rep.Posts {
+		post, ok := account.Post(postRep.UUID)
+		if !ok {
+
+			// posts without a known identity are new additions.
+			postUUID := postRep.UUID
+			if postUUID == u.Nil {
+				postUUID = u.Must(u.NewV4())
+			}
+			p, err := domain.NewPost(domain.PostParameters{
+				UUID:       postUUID,
+				Title:      postRep.Title,
+				Content:    postRep.Content,
+				Draft:      postRep.Draft,
+				Likes:      postRep.Likes,
+				AuthorUUID: account.UUID(),
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			})
+			if err != nil {
+				return err
+			}
+			posts = append(posts, p)
+			continue
+		}
+		if err := applyPost(&post, postRep, now); err != nil {
+			return err
+		}
+		posts = append(posts, post)
+	}
+	account.SetPosts(posts)
+	return account.SetUpdatedAt(now)
+}
+
+// applyPost applies the state described by the representation to the post
+// through its setters, so that the domain validation is enforced. The post
+// is only considered updated if its state has changed.
+func applyPost(post *domain.Post, rep j.Post, now time.Time) error {
+	if rep.UUID != post.UUID() {
+		return mismatchedUUIDProblem()
+	}
+	if rep.Title == post.Title() &&
+		rep.Content == post.Content() &&
+		rep.Draft == post.IsDraft() &&
+		rep.Likes == post.Likes() {
+		return nil
+	}
+	post.SetTitle(rep.Title)
+	post.SetContent(rep.Content)
+	post.SetDraft(rep.Draft)
+	if err := post.SetLikes(rep.Likes); err != nil {
+		return err
+	}
+	return post.SetUpdatedAt(now)
+}
+
+// mismatchedUUIDProblem constructs the problem describing a request that
+// attempts to change the identity of a resource.
+func mismatchedUUIDProblem() *problem {
+	return newProblem(problemTypeMismatchedUUID, errMismatchedUUID, fieldError{
+		field: "uuid",
+		cause: errMismatchedUUID,
+	})
+}
@@ -0,0 +1,365 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/freerware/negotiator/representation"
+	j "github.com/freerware/tutor/api/representations/json"
+	p "github.com/freerware/tutor/api/representations/protobuf"
+	x "github.com/freerware/tutor/api/representations/xml"
+	y "github.com/freerware/tutor/api/representations/yaml"
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/config"
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type PostResourceResult struct {
+	fx.Out
+
+	PostResource     PostResource
+	MuxConfiguration server.MuxConfiguration `group:"muxConfigurations"`
+}
+
+type PostResourceParameters struct {
+	fx.In
+
+	AccountService     app.AccountService
+	IdempotencyService app.IdempotencyService
+	Configuration      config.Configuration
+	Logger             *zap.Logger
+}
+
+type PostResource struct {
+	accountService app.AccountService
+	negotiation    negotiation
+	compression    compression
+	idempotency    idempotency
+	logger         *zap.Logger
+}
+
+func NewPostResource(
+	parameters PostResourceParameters,
+) (PostResourceResult, error) {
+	n, err := newNegotiation(parameters.Configuration)
+	if err != nil {
+		return PostResourceResult{}, err
+	}
+	p := PostResource{
+		accountService: parameters.AccountService,
+		negotiation:    n,
+		compression:    newCompression(parameters.Configuration.Compression),
+		idempotency:    newIdempotency(parameters.IdempotencyService, parameters.Configuration, parameters.Logger),
+		logger:         parameters.Logger,
+	}
+	return PostResourceResult{
+		PostResource:     p,
+		MuxConfiguration: p.MuxConfiguration(),
+	}, nil
+}
+
+func (pr *PostResource) List(w http.ResponseWriter, request *http.Request) {
+
+	// retrieve the account uuid.
+	vars := mux.Vars(request)
+	accountUUID, err := u.FromString(vars["uuid"])
+	if err != nil {
+		writeError(w, request, pr.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
+
+	// retrieve the account.
+	account, err := pr.accountService.Get(request.Context(), accountUUID)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	posts := account.Posts()
+	location := resourceLocation(request)
+	representations, err := pr.compression.compress(postsRepresentations(location, posts...)...)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	// negotiate.
+	if err = pr.negotiation.negotiate(w, request, representations...); err != nil {
+		writeError(w, request, pr.logger, err)
+	}
+}
+
+// postsRepresentations constructs the uncompressed representations of the
+// collection of posts, each located at the variant resource that serves it.
+func postsRepresentations(location url.URL, posts ...domain.Post) []representation.Representation {
+	jposts := j.NewPostCollection(posts...)
+	yposts := y.NewPostCollection(posts...)
+	xposts := x.NewPostCollection(posts...)
+	pposts := p.NewPostCollection(posts...)
+	return located(location, &jposts, &yposts, &xposts, &pposts)
+}
+
+func (pr *PostResource) Get(w http.ResponseWriter, request *http.Request) {
+
+	// retrieve the account and post uuids.
+	accountUUID, postUUID, err := postUUIDs(request)
+	if err != nil {
+		writeError(w, request, pr.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
+
+	// retrieve the post.
+	post, err := pr.accountService.GetPost(request.Context(), accountUUID, postUUID)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	representations, err := pr.representations(post, resourceLocation(request))
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	// evaluate the preconditions against the representation to be served.
+	selected, err := pr.negotiation.selected(request, representations...)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+	if selected != nil {
+		writeValidators(w, selected)
+	}
+	if status := preconditions(request, selected, representations...); status != 0 {
+		writePreconditionStatus(w, request, pr.logger, status)
+		return
+	}
+
+	// negotiate.
+	if err = pr.negotiation.negotiate(w, request, representations...); err != nil {
+		writeError(w, request, pr.logger, err)
+	}
+}
+
+// representations constructs the available representations of the post,
+// along with their validators.
+func (pr *PostResource) representations(
+	post domain.Post, location url.URL) ([]representation.Representation, error) {
+	reps, err := pr.compression.compress(postRepresentations(post, location)...)
+	if err != nil {
+		return nil, err
+	}
+	return validate(post.UpdatedAt(), reps...)
+}
+
+// postRepresentations constructs the uncompressed representations of the
+// post, each located at the variant resource that serves it.
+func postRepresentations(post domain.Post, location url.URL) []representation.Representation {
+	jpost := j.NewPost(post)
+	ypost := y.NewPost(post)
+	xpost := x.NewPost(post)
+	ppost := p.NewPost(post)
+	return located(location, &jpost, &ypost, &xpost, &ppost)
+}
+
+// precondition constructs the precondition described by the conditional
+// headers of an unsafe request, which is evaluated against the current state
+// of the post, if it exists. Posts that are required to exist are not found
+// otherwise.
+func (pr *PostResource) precondition(
+	request *http.Request, postUUID u.UUID, required bool) app.Precondition {
+	return func(account *domain.Account) error {
+		var current []representation.Representation
+		if post, ok := account.Post(postUUID); ok {
+			var err error
+			if current, err = pr.representations(post, resourceLocation(request)); err != nil {
+				return err
+			}
+		} else if required {
+			return app.ErrPostNotFound
+		}
+		if status := preconditions(request, nil, current...); status != 0 {
+			return newProblem(problemTypePreconditionFailed, errPreconditionFailed)
+		}
+		return nil
+	}
+}
+
+func (pr *PostResource) CreateAndAppend(
+	w http.ResponseWriter, request *http.Request) {
+
+	// retrieve the account uuid.
+	vars := mux.Vars(request)
+	accountUUID, err := u.FromString(vars["uuid"])
+	if err != nil {
+		writeError(w, request, pr.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
+
+	// decode the request body.
+	representation, err := readPost(w, request, pr.compression)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	now := time.Now()
+	post, err := domain.NewPost(domain.PostParameters{
+		UUID:       u.Must(u.NewV4()),
+		Title:      representation.Title,
+		Content:    representation.Content,
+		Draft:      representation.Draft,
+		Likes:      representation.Likes,
+		AuthorUUID: accountUUID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	// add the post.
+	uri := *request.URL
+	uri.Path = path.Join(uri.Path, post.UUID().String())
+	respond(request, 201, http.Header{"Content-Location": {uri.String()}})
+	err = pr.accountService.AddPost(request.Context(), accountUUID, post)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	w.Header().Add("Content-Location", uri.String())
+	w.WriteHeader(201)
+}
+
+func (pr *PostResource) Replace(w http.ResponseWriter, request *http.Request) {
+
+	// retrieve the account and post uuids.
+	accountUUID, postUUID, err := postUUIDs(request)
+	if err != nil {
+		writeError(w, request, pr.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
+
+	// decode the request body.
+	representation, err := readPost(w, request, pr.compression)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+	if representation.UUID != u.Nil && representation.UUID != postUUID {
+		writeError(w, request, pr.logger, newProblem(
+			problemTypeMismatchedUUID, errMismatchedUUID, fieldError{
+				field: "uuid",
+				cause: errMismatchedUUID,
+			}))
+		return
+	}
+
+	// posts that already exist retain their creation time, which the
+	// account service preserves.
+	now := time.Now()
+	post, err := domain.NewPost(domain.PostParameters{
+		UUID:       postUUID,
+		Title:      representation.Title,
+		Content:    representation.Content,
+		Draft:      representation.Draft,
+		Likes:      representation.Likes,
+		AuthorUUID: accountUUID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	// upsert the post, provided the client is replacing its current state.
+	respond(request, 204, nil)
+	err = pr.accountService.PutPost(
+		request.Context(), accountUUID, post, pr.precondition(request, postUUID, false))
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (pr *PostResource) Patch(w http.ResponseWriter, request *http.Request) {
+
+	// retrieve the account and post uuids.
+	accountUUID, postUUID, err := postUUIDs(request)
+	if err != nil {
+		writeError(w, request, pr.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
+
+	mediaType, document, err := readPatch(w, request, pr.compression)
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	// apply the patch to the current state of the post.
+	respond(request, 204, nil)
+	err = pr.accountService.Alter(request.Context(), accountUUID, func(account *domain.Account) error {
+		if err := pr.precondition(request, postUUID, true)(account); err != nil {
+			return err
+		}
+		post, _ := account.Post(postUUID)
+		patched := j.Post{}
+		if err := applyPatch(j.NewPost(post), mediaType, document, &patched); err != nil {
+			return err
+		}
+		if err := applyPost(&post, patched, time.Now()); err != nil {
+			return err
+		}
+		return account.ReplacePost(post)
+	})
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+func (pr *PostResource) Delete(w http.ResponseWriter, request *http.Request) {
+
+	// retrieve the account and post uuids.
+	accountUUID, postUUID, err := postUUIDs(request)
+	if err != nil {
+		writeError(w, request, pr.logger, newProblem(problemTypeMalformedUUID, err))
+		return
+	}
+
+	// delete the post, provided the client is deleting its current state.
+	respond(request, 204, nil)
+	err = pr.accountService.DeletePost(
+		request.Context(), accountUUID, postUUID, pr.precondition(request, postUUID, true))
+	if err != nil {
+		writeError(w, request, pr.logger, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// postUUIDs retrieves the account and post UUIDs from the request path.
+func postUUIDs(request *http.Request) (accountUUID, postUUID u.UUID, err error) {
+	vars := mux.Vars(request)
+	if accountUUID, err = u.FromString(vars["uuid"]); err != nil {
+		return
+	}
+	postUUID, err = u.FromString(vars["postUUID"])
+	return
+}
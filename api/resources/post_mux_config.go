@@ -0,0 +1,172 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/freerware/tutor/api/openapi"
+	j "github.com/freerware/tutor/api/representations/json"
+	"github.com/freerware/tutor/api/server"
+	"github.com/freerware/tutor/domain"
+)
+
+func (pr *PostResource) MuxConfiguration() (config server.MuxConfiguration) {
+	posts := negotiated(j.Posts{}, postsRepresentations(url.URL{})...)
+	post := negotiated(j.Post{}, postRepresentations(domain.Post{}, url.URL{})...)
+	list := openapi.Operation{
+		Summary: "Retrieve the posts of an existing account",
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusOK, Content: posts},
+			multipleChoices(),
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable),
+	}
+	listVariant := variantOperation("Retrieve the posts of an existing account in a single format", list)
+	create := secured(idempotent(openapi.Operation{
+		Summary:     "Create a new post for an existing account",
+		RequestBody: &openapi.Content{MediaTypes: keys(postDecoders), Schema: j.Post{}},
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusCreated, Headers: []string{"Content-Location"}},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusRequestEntityTooLarge,
+			http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity),
+	}))
+	get := openapi.Operation{
+		Summary:    "Retrieve an existing post",
+		Parameters: conditionalParameters,
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusOK, Headers: []string{"ETag", "Last-Modified"}, Content: post},
+			{Status: http.StatusNotModified},
+			multipleChoices(),
+		}, http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable, http.StatusPreconditionFailed),
+	}
+	getVariant := variantOperation("Retrieve an existing post in a single format", get)
+	replace := secured(idempotent(openapi.Operation{
+		Summary:     "Upsert a post",
+		Parameters:  conditionalParameters,
+		RequestBody: &openapi.Content{MediaTypes: keys(postDecoders), Schema: j.Post{}},
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusNoContent},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+			http.StatusPreconditionFailed, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity),
+	}))
+	modify := secured(idempotent(openapi.Operation{
+		Summary:     "Modify an existing post",
+		Parameters:  conditionalParameters,
+		RequestBody: patchDocument(),
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusNoContent},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusConflict,
+			http.StatusPreconditionFailed, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType,
+			http.StatusUnprocessableEntity),
+	}))
+	remove := secured(idempotent(openapi.Operation{
+		Summary:    "Remove an existing post",
+		Parameters: conditionalParameters,
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusNoContent},
+		}, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound, http.StatusPreconditionFailed),
+	}))
+
+	// unsafe requests must be authenticated, and can be retried with an
+	// idempotency key.
+	unsafe := []server.Middleware{authenticated(pr.logger), pr.idempotency.middleware()}
+
+	config = server.MuxConfiguration{
+		PathPrefix: "/accounts/{uuid}/posts",
+		Handlers: []server.HandlerConfiguration{
+			{
+				Path:        "." + variantFormat,
+				HandlerFunc: pr.List,
+				Methods:     []string{"GET"},
+				Operation:   listVariant,
+			},
+			{
+				Path:        "/{postUUID}." + variantFormat,
+				HandlerFunc: pr.Get,
+				Methods:     []string{"GET"},
+				Operation:   getVariant,
+			},
+			{
+				Path:        "",
+				HandlerFunc: pr.List,
+				Methods:     []string{"GET"},
+				Operation:   list,
+			},
+			{
+				Path:        "/",
+				HandlerFunc: pr.List,
+				Methods:     []string{"GET"},
+				Operation:   list,
+			},
+			{
+				Path:        "",
+				HandlerFunc: pr.CreateAndAppend,
+				Middlewares: unsafe,
+				Methods:     []string{"POST"},
+				Operation:   create,
+			},
+			{
+				Path:        "/",
+				HandlerFunc: pr.CreateAndAppend,
+				Middlewares: unsafe,
+				Methods:     []string{"POST"},
+				Operation:   create,
+			},
+			{
+				Path:        "/{postUUID}",
+				HandlerFunc: pr.Get,
+				Methods:     []string{"GET"},
+				Operation:   get,
+			},
+			{
+				Path:        "/{postUUID}/",
+				HandlerFunc: pr.Get,
+				Methods:     []string{"GET"},
+				Operation:   get,
+			},
+			{
+				Path:        "/{postUUID}",
+				HandlerFunc: pr.Replace,
+				Middlewares: unsafe,
+				Methods:     []string{"PUT"},
+				Operation:   replace,
+			},
+			{
+				Path:        "/{postUUID}/",
+				HandlerFunc: pr.Replace,
+				Middlewares: unsafe,
+				Methods:     []string{"PUT"},
+				Operation:   replace,
+			},
+			{
+				Path:        "/{postUUID}",
+				HandlerFunc: pr.Patch,
+				Middlewares: unsafe,
+				Methods:     []string{"PATCH"},
+				Operation:   modify,
+			},
+			{
+				Path:        "/{postUUID}/",
+				HandlerFunc: pr.Patch,
+				Middlewares: unsafe,
+				Methods:     []string{"PATCH"},
+				Operation:   modify,
+			},
+			{
+				Path:        "/{postUUID}",
+				HandlerFunc: pr.Delete,
+				Middlewares: unsafe,
+				Methods:     []string{"DELETE"},
+				Operation:   remove,
+			},
+			{
+				Path:        "/{postUUID}/",
+				HandlerFunc: pr.Delete,
+				Middlewares: unsafe,
+				Methods:     []string{"DELETE"},
+				Operation:   remove,
+			},
+		},
+	}
+	return
+}
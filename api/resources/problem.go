@@ -0,0 +1,431 @@
+package resources
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/freerware/negotiator/representation"
+	"github.com/freerware/tutor/api/i18n"
+	"github.com/freerware/tutor/api/patch"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	p "github.com/freerware/tutor/api/representations/protobuf"
+	x "github.com/freerware/tutor/api/representations/xml"
+	y "github.com/freerware/tutor/api/representations/yaml"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/zap"
+)
+
+const problemTypeURIPrefix = "urn:freerware:tutor:problem:"
+
+// problemType identifies a category of problem, as described in RFC 9457.
+type problemType struct {
+	uri    string
+	title  string
+	status int
+}
+
+// The problem types that can be communicated to clients.
+var (
+	problemTypeMalformedUUID = problemType{
+		uri:    problemTypeURIPrefix + "malformed-uuid",
+		title:  "Malformed UUID",
+		status: http.StatusBadRequest,
+	}
+	problemTypeMismatchedUUID = problemType{
+		uri:    problemTypeURIPrefix + "mismatched-uuid",
+		title:  "Mismatched UUID",
+		status: http.StatusBadRequest,
+	}
+	problemTypeInvalidFilter = problemType{
+		uri:    problemTypeURIPrefix + "invalid-filter",
+		title:  "Invalid filter parameters",
+		status: http.StatusBadRequest,
+	}
+	problemTypeInvalidParameter = problemType{
+		uri:    problemTypeURIPrefix + "invalid-parameter",
+		title:  "Invalid query parameter",
+		status: http.StatusBadRequest,
+	}
+	problemTypeInvalidPage = problemType{
+		uri:    problemTypeURIPrefix + "invalid-page",
+		title:  "Invalid pagination parameters",
+		status: http.StatusBadRequest,
+	}
+	problemTypeMalformedBody = problemType{
+		uri:    problemTypeURIPrefix + "malformed-body",
+		title:  "Malformed request body",
+		status: http.StatusBadRequest,
+	}
+	problemTypeInvalidState = problemType{
+		uri:    problemTypeURIPrefix + "invalid-state",
+		title:  "Invalid resource state",
+		status: http.StatusUnprocessableEntity,
+	}
+	problemTypeUnauthorized = problemType{
+		uri:    problemTypeURIPrefix + "unauthorized",
+		title:  "Unauthorized",
+		status: http.StatusUnauthorized,
+	}
+	problemTypeForbidden = problemType{
+		uri:    problemTypeURIPrefix + "forbidden",
+		title:  "Forbidden",
+		status: http.StatusForbidden,
+	}
+	problemTypeUsernameTaken = problemType{
+		uri:    problemTypeURIPrefix + "username-taken",
+		title:  "Username taken",
+		status: http.StatusConflict,
+	}
+	problemTypePostUUIDTaken = problemType{
+		uri:    problemTypeURIPrefix + "post-uuid-taken",
+		title:  "Post UUID taken",
+		status: http.StatusConflict,
+	}
+	problemTypeAccountNotFound = problemType{
+		uri:    problemTypeURIPrefix + "account-not-found",
+		title:  "Account not found",
+		status: http.StatusNotFound,
+	}
+	problemTypePostNotFound = problemType{
+		uri:    problemTypeURIPrefix + "post-not-found",
+		title:  "Post not found",
+		status: http.StatusNotFound,
+	}
+	problemTypePreconditionFailed = problemType{
+		uri:    problemTypeURIPrefix + "precondition-failed",
+		title:  "Precondition failed",
+		status: http.StatusPreconditionFailed,
+	}
+	problemTypeNotAcceptable = problemType{
+		uri:    problemTypeURIPrefix + "not-acceptable",
+		title:  "Not acceptable",
+		status: http.StatusNotAcceptable,
+	}
+	problemTypeUnsupportedMediaType = problemType{
+		uri:    problemTypeURIPrefix + "unsupported-media-type",
+		title:  "Unsupported media type",
+		status: http.StatusUnsupportedMediaType,
+	}
+	problemTypeUnsupportedContentCoding = problemType{
+		uri:    problemTypeURIPrefix + "unsupported-content-coding",
+		title:  "Unsupported content coding",
+		status: http.StatusUnsupportedMediaType,
+	}
+	problemTypeRequestTooLarge = problemType{
+		uri:    problemTypeURIPrefix + "request-too-large",
+		title:  "Request body too large",
+		status: http.StatusRequestEntityTooLarge,
+	}
+	problemTypeMalformedPatch = problemType{
+		uri:    problemTypeURIPrefix + "malformed-patch",
+		title:  "Malformed patch document",
+		status: http.StatusBadRequest,
+	}
+	problemTypePatchConflict = problemType{
+		uri:    problemTypeURIPrefix + "patch-conflict",
+		title:  "Patch test operation failed",
+		status: http.StatusConflict,
+	}
+	problemTypeUnprocessablePatch = problemType{
+		uri:    problemTypeURIPrefix + "unprocessable-patch",
+		title:  "Patch document cannot be applied",
+		status: http.StatusUnprocessableEntity,
+	}
+	problemTypeInvalidIdempotencyKey = problemType{
+		uri:    problemTypeURIPrefix + "invalid-idempotency-key",
+		title:  "Invalid idempotency key",
+		status: http.StatusBadRequest,
+	}
+	problemTypeIdempotencyKeyInUse = problemType{
+		uri:    problemTypeURIPrefix + "idempotency-key-in-use",
+		title:  "Idempotency key in use",
+		status: http.StatusConflict,
+	}
+	problemTypeIdempotencyKeyReused = problemType{
+		uri:    problemTypeURIPrefix + "idempotency-key-reused",
+		title:  "Idempotency key reused",
+		status: http.StatusUnprocessableEntity,
+	}
+	problemTypeInternal = problemType{
+		uri:    problemTypeURIPrefix + "internal",
+		title:  "Internal server error",
+		status: http.StatusInternalServerError,
+	}
+)
+
+// domainErrorFields associates domain validation errors with the members of
+// the request they originate from.
+var domainErrorFields = map[error]string{
+	domain.ErrFutureCreatedAt:      "createdAt",
+	domain.ErrFutureUpdatedAt:      "updatedAt",
+	domain.ErrInvalidUpdatedAt:     "updatedAt",
+	domain.ErrFutureDeletedAt:      "deletedAt",
+	domain.ErrInvalidDeletedAt:     "deletedAt",
+	domain.ErrNegativeLikes:        "likes",
+	domain.ErrPostAlreadyPublished: "isDraft",
+	app.ErrInvalidPassword:         "password",
+	app.ErrIncorrectPassword:       "currentPassword",
+}
+
+// messageKeys associates errors with the keys of the messages that explain
+// them to clients.
+var messageKeys = map[error]string{
+	domain.ErrFutureCreatedAt:      "domain.futureCreatedAt",
+	domain.ErrFutureUpdatedAt:      "domain.futureUpdatedAt",
+	domain.ErrInvalidUpdatedAt:     "domain.invalidUpdatedAt",
+	domain.ErrFutureDeletedAt:      "domain.futureDeletedAt",
+	domain.ErrInvalidDeletedAt:     "domain.invalidDeletedAt",
+	domain.ErrNegativeLikes:        "domain.negativeLikes",
+	domain.ErrPostAlreadyPublished: "domain.postAlreadyPublished",
+	domain.ErrPostNotFound:         "domain.postNotFound",
+	app.ErrAccountNotFound:         "application.accountNotFound",
+	app.ErrPostNotFound:            "application.postNotFound",
+	app.ErrAccountAlreadyExists:    "application.accountAlreadyExists",
+	app.ErrImportAborted:           "application.importAborted",
+	app.ErrIdempotencyKeyInUse:     "application.idempotencyKeyInUse",
+	app.ErrInvalidCredentials:      "application.invalidCredentials",
+	app.ErrUnauthenticated:         "application.unauthenticated",
+	app.ErrIncorrectPassword:       "application.incorrectPassword",
+	app.ErrInvalidPassword:         "application.invalidPassword",
+	app.ErrUsernameTaken:           "application.usernameTaken",
+	app.ErrForbidden:               "application.forbidden",
+	app.ErrPostUUIDTaken:           "application.postUUIDTaken",
+	errMismatchedUUID:              "resources.mismatchedUUID",
+	errPreconditionFailed:          "resources.preconditionFailed",
+	errInvalidIdempotencyKey:       "resources.invalidIdempotencyKey",
+	errIdempotencyKeyReused:        "resources.idempotencyKeyReused",
+	ErrInvalidPageSize:             "resources.invalidPageSize",
+	ErrInvalidPageToken:            "resources.invalidPageToken",
+	patch.ErrUnsupportedMediaType:  "patch.unsupportedMediaType",
+	patch.ErrMalformedPatch:        "patch.malformedPatch",
+	patch.ErrMalformedDocument:     "patch.malformedDocument",
+	patch.ErrPathNotFound:          "patch.pathNotFound",
+	patch.ErrTestFailed:            "patch.testFailed",
+}
+
+var (
+	// errMismatchedUUID indicates that the UUID within a request body
+	// disagrees with the UUID within the request URI.
+	errMismatchedUUID = errors.New("resources: UUID in the request body does not match the request URI")
+
+	// errPreconditionFailed indicates that the resource has changed since the
+	// client last retrieved it.
+	errPreconditionFailed = errors.New("resources: the resource has been modified since it was last retrieved")
+)
+
+// problem is an error that is communicated to clients as problem details.
+type problem struct {
+	problemType
+
+	cause  error
+	fields []fieldError
+}
+
+// fieldError is a problem with a particular member of the request.
+type fieldError struct {
+	field string
+	cause error
+}
+
+// newProblem constructs a problem of the provided type caused by the
+// provided error.
+func newProblem(t problemType, cause error, fields ...fieldError) *problem {
+	return &problem{problemType: t, cause: cause, fields: fields}
+}
+
+func (p *problem) Error() string {
+	if p.cause == nil {
+		return p.title
+	}
+	return p.cause.Error()
+}
+
+func (p *problem) Unwrap() error { return p.cause }
+
+// details provides the problem details describing the problem in the
+// provided locale. Only problems caused by the client are explained.
+func (p *problem) details(request *http.Request, locale string) r.Problem {
+	details := r.Problem{
+		Type:     p.uri,
+		Title:    i18n.Default.Message(locale, "problem."+strings.TrimPrefix(p.uri, problemTypeURIPrefix)),
+		Status:   p.status,
+		Instance: request.URL.RequestURI(),
+	}
+	if p.cause != nil && p.status < http.StatusInternalServerError {
+		details.Detail = message(locale, p.cause)
+	}
+	for _, fe := range p.fields {
+		details.Errors = append(details.Errors, r.FieldError{
+			Field:  fe.field,
+			Detail: message(locale, fe.cause),
+		})
+	}
+	return details
+}
+
+// message explains the provided error in the provided locale, falling back
+// to the error itself when the catalog has no message for it.
+func message(locale string, err error) string {
+	if m, ok := localized(locale, err); ok {
+		return m
+	}
+	return err.Error()
+}
+
+// localized explains the provided error in the provided locale, indicating
+// if the catalog has a message for it.
+func localized(locale string, err error) (string, bool) {
+	var e *i18n.Error
+	if errors.As(err, &e) {
+		return i18n.Default.Message(locale, e.Key(), e.Args()...), true
+	}
+	for known, key := range messageKeys {
+		if errors.Is(err, known) {
+			return i18n.Default.Message(locale, key), true
+		}
+	}
+	return "", false
+}
+
+// classify determines the problem that describes the provided error.
+func classify(err error) *problem {
+	var p *problem
+	if errors.As(err, &p) {
+		return p
+	}
+	switch {
+	case errors.Is(err, app.ErrAccountNotFound):
+		return newProblem(problemTypeAccountNotFound, err)
+	case errors.Is(err, app.ErrPostNotFound), errors.Is(err, domain.ErrPostNotFound):
+		return newProblem(problemTypePostNotFound, err)
+	case errors.Is(err, app.ErrIdempotencyKeyInUse):
+		return newProblem(problemTypeIdempotencyKeyInUse, err)
+	case errors.Is(err, app.ErrInvalidCredentials), errors.Is(err, app.ErrUnauthenticated):
+		return newProblem(problemTypeUnauthorized, err)
+	case errors.Is(err, app.ErrForbidden):
+		return newProblem(problemTypeForbidden, err)
+	case errors.Is(err, app.ErrUsernameTaken):
+		return newProblem(problemTypeUsernameTaken, err, fieldError{
+			field: "primaryCredential",
+			cause: app.ErrUsernameTaken,
+		})
+	case errors.Is(err, app.ErrPostUUIDTaken):
+		return newProblem(problemTypePostUUIDTaken, err, fieldError{
+			field: "uuid",
+			cause: app.ErrPostUUIDTaken,
+		})
+	}
+	for domainErr, field := range domainErrorFields {
+		if errors.Is(err, domainErr) {
+			return newProblem(problemTypeInvalidState, err, fieldError{
+				field: field,
+				cause: domainErr,
+			})
+		}
+	}
+	return newProblem(problemTypeInternal, err)
+}
+
+// writeError responds to the request with problem details describing the
+// provided error, in the format and language that best suit the client.
+func writeError(
+	w http.ResponseWriter, request *http.Request, logger *zap.Logger, err error) {
+
+	logger = infrastructure.LoggerFrom(request.Context(), logger)
+	p := classify(err)
+	if p.status >= http.StatusInternalServerError {
+		logger.Error("failed to handle request",
+			zap.String("method", request.Method),
+			zap.String("path", request.URL.Path),
+			zap.Error(err))
+	}
+
+	// the problem details are written in every locale, leaving the negotiator
+	// to choose amongst the languages and formats.
+	negotiated, representations := localize(request, func(locale string) []representation.Representation {
+		return problemRepresentations(p.details(request, locale), locale)
+	})
+	negotiated = negotiable(negotiated)
+
+	// clients are challenged to authenticate with either scheme.
+	if p.status == http.StatusUnauthorized {
+		w.Header().Add("WWW-Authenticate", `Basic realm="`+authenticationRealm+`", charset="UTF-8"`)
+		w.Header().Add("WWW-Authenticate", `Bearer realm="`+authenticationRealm+`"`)
+	}
+	w.Header().Add("Vary", "Accept-Language")
+	if err := writeChosen(w, negotiated, p.status, representations...); err != nil {
+		logger.Error("failed to serialize problem details", zap.Error(err))
+		http.Error(w, p.title, p.status)
+	}
+}
+
+// writeChosen responds to the request with the provided status, along with
+// the representation chosen the same way successful responses are
+// negotiated, falling back to the first representation when nothing is
+// acceptable.
+func writeChosen(
+	w http.ResponseWriter,
+	request *http.Request,
+	status int,
+	representations ...representation.Representation) error {
+	chosen, err := choose(request, representations...)
+	if err != nil || chosen == nil {
+		chosen = representations[0]
+	}
+	b, err := chosen.Bytes()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", chosen.ContentType())
+	w.Header().Set("Content-Language", chosen.ContentLanguage())
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(status)
+	w.Write(b)
+	return nil
+}
+
+// problemRepresentations constructs the available representations of the
+// provided problem details, written in the provided locale. The problem
+// media types are preferred, while the generic media types accommodate
+// clients that do not understand them.
+func problemRepresentations(details r.Problem, locale string) []representation.Representation {
+	jerr := j.NewError(details)
+	jerr.SetContentLanguage(locale)
+	xerr := x.NewError(details)
+	xerr.SetContentLanguage(locale)
+	yerr := y.NewError(details)
+	yerr.SetContentLanguage(locale)
+	gjerr := j.NewError(details)
+	gjerr.SetContentType("application/json")
+	gjerr.SetContentLanguage(locale)
+	gjerr.SetSourceQuality(0.9)
+	gxerr := x.NewError(details)
+	gxerr.SetContentType("application/xml")
+	gxerr.SetContentLanguage(locale)
+	gxerr.SetSourceQuality(0.9)
+	gyerr := y.NewError(details)
+	gyerr.SetContentType("application/yaml")
+	gyerr.SetContentLanguage(locale)
+	gyerr.SetSourceQuality(0.9)
+	gperr := p.NewError(details)
+	gperr.SetContentLanguage(locale)
+	gperr.SetSourceQuality(0.9)
+	return []representation.Representation{jerr, xerr, yerr, gjerr, gxerr, gyerr, gperr}
+}
+
+// writePreconditionStatus responds to a request whose preconditions were not
+// met with the provided status code.
+func writePreconditionStatus(
+	w http.ResponseWriter, request *http.Request, logger *zap.Logger, status int) {
+	if status == http.StatusNotModified {
+		w.WriteHeader(status)
+		return
+	}
+	writeError(w, request, logger, newProblem(problemTypePreconditionFailed, errPreconditionFailed))
+}
@@ -0,0 +1,50 @@
+package resources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/freerware/tutor/api/i18n"
+	app "github.com/freerware/tutor/application"
+	"go.uber.org/zap"
+)
+
+func TestWriteErrorNegotiatesLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		accept         string
+		language       string
+		contentType    string
+	}{
+		{language: i18n.DefaultLocale, contentType: "application/problem+json"},
+		{acceptLanguage: "de-DE", language: "de-DE", contentType: "application/problem+json"},
+		{acceptLanguage: "fr;q=0.5, es", language: "es-ES", contentType: "application/problem+json"},
+		{acceptLanguage: "de-DE", accept: "application/yaml", language: "de-DE", contentType: "application/yaml"},
+		{acceptLanguage: "ja", accept: "application/xml", language: i18n.DefaultLocale, contentType: "application/xml"},
+	}
+	for _, test := range tests {
+		t.Run(test.acceptLanguage+" "+test.accept, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
+			if test.acceptLanguage != "" {
+				request.Header.Set("Accept-Language", test.acceptLanguage)
+			}
+			if test.accept != "" {
+				request.Header.Set("Accept", test.accept)
+			}
+			w := httptest.NewRecorder()
+
+			writeError(w, request, zap.NewNop(), app.ErrAccountNotFound)
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("expected status %d, got %d", http.StatusNotFound, w.Code)
+			}
+			if language := w.Header().Get("Content-Language"); language != test.language {
+				t.Errorf("expected language %s, got %s", test.language, language)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != test.contentType {
+				t.Errorf("expected media type %s, got %s", test.contentType, contentType)
+			}
+		})
+	}
+}
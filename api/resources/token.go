@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/freerware/negotiator/representation"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	x "github.com/freerware/tutor/api/representations/xml"
+	y "github.com/freerware/tutor/api/representations/yaml"
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type TokenResourceResult struct {
+	fx.Out
+
+	TokenResource    TokenResource
+	MuxConfiguration server.MuxConfiguration `group:"muxConfigurations"`
+}
+
+type TokenResourceParameters struct {
+	fx.In
+
+	AuthenticationService app.AuthenticationService
+	Configuration         config.Configuration
+	Logger                *zap.Logger
+}
+
+// TokenResource issues bearer tokens to clients that authenticate with a
+// password, so that subsequent requests need not carry the password.
+type TokenResource struct {
+	authenticationService app.AuthenticationService
+	negotiation           negotiation
+	logger                *zap.Logger
+}
+
+func NewTokenResource(parameters TokenResourceParameters) (TokenResourceResult, error) {
+	n, err := newNegotiation(parameters.Configuration)
+	if err != nil {
+		return TokenResourceResult{}, err
+	}
+	tr := TokenResource{
+		authenticationService: parameters.AuthenticationService,
+		negotiation:           n,
+		logger:                parameters.Logger,
+	}
+	return TokenResourceResult{
+		TokenResource:    tr,
+		MuxConfiguration: tr.MuxConfiguration(),
+	}, nil
+}
+
+func (tr *TokenResource) Create(w http.ResponseWriter, request *http.Request) {
+
+	// issue a token to the principal.
+	principal, _ := app.PrincipalFrom(request.Context())
+	token, err := tr.authenticationService.Issue(principal)
+	if err != nil {
+		writeError(w, request, tr.logger, err)
+		return
+	}
+
+	// negotiate.
+	t := r.Token{
+		AccessToken: token.Value,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(time.Until(token.ExpiresAt).Round(time.Second).Seconds()),
+		ExpiresAt:   token.ExpiresAt,
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	if err = tr.negotiation.negotiate(w, request, tokenRepresentations(t)...); err != nil {
+		writeError(w, request, tr.logger, err)
+	}
+}
+
+// tokenRepresentations constructs the available representations of the
+// token.
+func tokenRepresentations(t r.Token) []representation.Representation {
+	jrep := j.NewToken(t)
+	yrep := y.NewToken(t)
+	xrep := x.NewToken(t)
+	return []representation.Representation{&jrep, &yrep, &xrep}
+}
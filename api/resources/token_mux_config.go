@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"net/http"
+
+	"github.com/freerware/tutor/api/openapi"
+	r "github.com/freerware/tutor/api/representations"
+	j "github.com/freerware/tutor/api/representations/json"
+	"github.com/freerware/tutor/api/server"
+	app "github.com/freerware/tutor/application"
+)
+
+func (tr *TokenResource) MuxConfiguration() server.MuxConfiguration {
+	token := negotiated(j.Token{}, tokenRepresentations(r.Token{})...)
+	create := secured(openapi.Operation{
+		Summary: "Issue a bearer token",
+		Responses: responses([]openapi.Response{
+			{Status: http.StatusOK, Headers: []string{"Cache-Control"}, Content: token},
+			multipleChoices(),
+		}, http.StatusNotAcceptable),
+	}, openapi.SecurityBasic)
+
+	// tokens are only issued in exchange for a password, so that a token
+	// cannot be renewed indefinitely.
+	protected := []server.Middleware{authenticated(tr.logger, app.AuthenticatedWithPassword)}
+
+	return server.MuxConfiguration{
+		PathPrefix: "/tokens",
+		Handlers: []server.HandlerConfiguration{
+			{
+				Path:        "",
+				HandlerFunc: tr.Create,
+				Middlewares: protected,
+				Methods:     []string{"POST"},
+				Operation:   create,
+			},
+			{
+				Path:        "/",
+				HandlerFunc: tr.Create,
+				Middlewares: protected,
+				Methods:     []string{"POST"},
+				Operation:   create,
+			},
+		},
+	}
+}
@@ -0,0 +1,318 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	r "github.com/freerware/tutor/api/representations"
+	p "github.com/freerware/tutor/api/representations/protobuf"
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+// accountService implements the gRPC account service on top of the
+// application account service.
+type accountService struct {
+	gen.UnimplementedAccountServiceServer
+
+	service app.AccountService
+	logger  *zap.Logger
+}
+
+func (as *accountService) GetAccount(
+	ctx context.Context, request *gen.GetAccountRequest) (*gen.Account, error) {
+
+	uuid, err := parseUUID("UUID", request.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve the account.
+	account, err := as.service.Get(ctx, uuid)
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return p.NewAccount(account).Account, nil
+}
+
+func (as *accountService) ListAccounts(
+	ctx context.Context, request *gen.ListAccountsRequest) (*gen.Accounts, error) {
+
+	// interpret the pagination parameters.
+	size := r.DefaultPageSize
+	if request.PageSize < 0 {
+		return nil, invalidArgument("pageSize must not be negative")
+	}
+	if request.PageSize > 0 {
+		size = min(int(request.PageSize), r.MaximumPageSize)
+	}
+	offset := 0
+	if request.PageToken != "" {
+		var err error
+		if offset, err = r.DecodePageToken(request.PageToken); err != nil {
+			return nil, statusError(ctx, as.logger, err)
+		}
+	}
+
+	// retrieve the page of accounts.
+	accounts, total, err := as.service.List(ctx, offset, size)
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+
+	page := r.Page{Size: size, TotalCount: total}
+	if offset+len(accounts) < total {
+		page.NextPageToken = r.EncodePageToken(offset + size)
+	}
+	if offset > 0 {
+		page.PreviousPageToken = r.EncodePageToken(max(offset-size, 0))
+	}
+	collection := p.NewAccounts(page, accounts...).Accounts
+	collection.Links = nil
+	return collection, nil
+}
+
+func (as *accountService) CreateAccount(
+	ctx context.Context, request *gen.CreateAccountRequest) (*gen.Account, error) {
+	if request.Account == nil {
+		return nil, invalidArgument("account is required")
+	}
+
+	now := time.Now()
+	accountUUID := u.Must(u.NewV4())
+	posts := []domain.Post{}
+	for _, post := range request.Account.Posts {
+		created, err := newPost(post, u.Must(u.NewV4()), accountUUID, now, now)
+		if err != nil {
+			return nil, statusError(ctx, as.logger, err)
+		}
+		posts = append(posts, created)
+	}
+	account, err := domain.NewAccount(domain.AccountParameters{
+		UUID:      accountUUID,
+		GivenName: request.Account.GivenName,
+		Surname:   request.Account.Surname,
+		Username:  request.Account.Username,
+		Posts:     posts,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+
+	// create the account.
+	if err = as.service.Create(ctx, account, request.Account.Password); err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return p.NewAccount(account).Account, nil
+}
+
+func (as *accountService) PutAccount(
+	ctx context.Context, request *gen.PutAccountRequest) (*gen.Account, error) {
+	if request.Account == nil {
+		return nil, invalidArgument("account is required")
+	}
+	uuid, err := parseUUID("account.UUID", request.Account.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve the account.
+	existing, err := as.service.Get(ctx, uuid)
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+
+	// posts keep their identity and creation time across replacements.
+	now := time.Now()
+	posts := []domain.Post{}
+	for _, post := range request.Account.Posts {
+		postUUID := u.Must(u.NewV4())
+		if post.UUID != "" {
+			if postUUID, err = parseUUID("account.posts.UUID", post.UUID); err != nil {
+				return nil, err
+			}
+		}
+		createdAt := now
+		if e, ok := existing.Post(postUUID); ok {
+			createdAt = e.CreatedAt()
+		}
+		created, err := newPost(post, postUUID, uuid, createdAt, now)
+		if err != nil {
+			return nil, statusError(ctx, as.logger, err)
+		}
+		posts = append(posts, created)
+	}
+	account, err := domain.NewAccount(domain.AccountParameters{
+		UUID:      uuid,
+		GivenName: request.Account.GivenName,
+		Surname:   request.Account.Surname,
+		Username:  request.Account.Username,
+		Posts:     posts,
+		CreatedAt: existing.CreatedAt(),
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+
+	// upsert the account.
+	if err = as.service.Put(ctx, account); err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return p.NewAccount(account).Account, nil
+}
+
+func (as *accountService) DeleteAccount(
+	ctx context.Context, request *gen.DeleteAccountRequest) (*gen.DeleteAccountResponse, error) {
+
+	uuid, err := parseUUID("UUID", request.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// delete the account.
+	if err = as.service.Delete(ctx, uuid); err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return &gen.DeleteAccountResponse{}, nil
+}
+
+func (as *accountService) ListPosts(
+	ctx context.Context, request *gen.ListPostsRequest) (*gen.Posts, error) {
+
+	accountUUID, err := parseUUID("accountUUID", request.AccountUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve the account.
+	account, err := as.service.Get(ctx, accountUUID)
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return p.NewPostCollection(account.Posts()...).Posts, nil
+}
+
+func (as *accountService) GetPost(
+	ctx context.Context, request *gen.GetPostRequest) (*gen.Post, error) {
+
+	accountUUID, err := parseUUID("accountUUID", request.AccountUUID)
+	if err != nil {
+		return nil, err
+	}
+	postUUID, err := parseUUID("postUUID", request.PostUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve the post.
+	post, err := as.service.GetPost(ctx, accountUUID, postUUID)
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return p.NewPost(post).Post, nil
+}
+
+func (as *accountService) CreatePost(
+	ctx context.Context, request *gen.CreatePostRequest) (*gen.Post, error) {
+	if request.Post == nil {
+		return nil, invalidArgument("post is required")
+	}
+	accountUUID, err := parseUUID("accountUUID", request.AccountUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	post, err := newPost(request.Post, u.Must(u.NewV4()), accountUUID, now, now)
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+
+	// add the post.
+	if err = as.service.AddPost(ctx, accountUUID, post); err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return p.NewPost(post).Post, nil
+}
+
+func (as *accountService) PutPost(
+	ctx context.Context, request *gen.PutPostRequest) (*gen.Post, error) {
+	if request.Post == nil {
+		return nil, invalidArgument("post is required")
+	}
+	accountUUID, err := parseUUID("accountUUID", request.AccountUUID)
+	if err != nil {
+		return nil, err
+	}
+	postUUID, err := parseUUID("post.UUID", request.Post.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// posts that already exist retain their creation time.
+	now := time.Now()
+	createdAt := now
+	if existing, err := as.service.GetPost(ctx, accountUUID, postUUID); err == nil {
+		createdAt = existing.CreatedAt()
+	}
+	post, err := newPost(request.Post, postUUID, accountUUID, createdAt, now)
+	if err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+
+	// upsert the post.
+	if err = as.service.PutPost(ctx, accountUUID, post); err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return p.NewPost(post).Post, nil
+}
+
+func (as *accountService) DeletePost(
+	ctx context.Context, request *gen.DeletePostRequest) (*gen.DeletePostResponse, error) {
+
+	accountUUID, err := parseUUID("accountUUID", request.AccountUUID)
+	if err != nil {
+		return nil, err
+	}
+	postUUID, err := parseUUID("postUUID", request.PostUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// delete the post.
+	if err = as.service.DeletePost(ctx, accountUUID, postUUID); err != nil {
+		return nil, statusError(ctx, as.logger, err)
+	}
+	return &gen.DeletePostResponse{}, nil
+}
+
+// newPost constructs a post from the provided message. Server-managed
+// members, such as the identity and timestamps, are provided separately.
+func newPost(
+	post *gen.Post, uuid, authorUUID u.UUID, createdAt, updatedAt time.Time) (domain.Post, error) {
+	return domain.NewPost(domain.PostParameters{
+		UUID:       uuid,
+		Title:      post.Title,
+		Content:    post.Content,
+		Draft:      post.IsDraft,
+		Likes:      int(post.Likes),
+		AuthorUUID: authorUUID,
+		CreatedAt:  createdAt,
+		UpdatedAt:  updatedAt,
+	})
+}
+
+// parseUUID parses the UUID provided for the named member of the request.
+func parseUUID(member, uuid string) (u.UUID, error) {
+	parsed, err := u.FromString(uuid)
+	if err != nil {
+		return u.Nil, invalidArgument("%s is malformed: %v", member, err)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,51 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// protectedMethods are the methods that must be called on behalf of an
+// authenticated principal, since they change accounts or their posts.
+// Accounts are created by unauthenticated clients, as they are over HTTP.
+var protectedMethods = map[string]bool{
+	gen.AccountService_PutAccount_FullMethodName:    true,
+	gen.AccountService_DeleteAccount_FullMethodName: true,
+	gen.AccountService_CreatePost_FullMethodName:    true,
+	gen.AccountService_PutPost_FullMethodName:       true,
+	gen.AccountService_DeletePost_FullMethodName:    true,
+}
+
+// authentication constructs the interceptor that authenticates calls bearing
+// authorization metadata, which carries the same credentials as the
+// Authorization header of an HTTP request. The principal of an authenticated
+// call is available to the methods within the context of the call, as is a
+// logger describing the call.
+func authentication(service app.AuthenticationService, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		request any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (any, error) {
+		ctx = infrastructure.WithLogger(ctx, logger.With(zap.String("method", info.FullMethod)))
+		if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+			principal, err := service.Identify(ctx, values[0])
+			if err != nil {
+				return nil, statusError(ctx, logger, err)
+			}
+			ctx = app.WithPrincipal(ctx, principal)
+			ctx = infrastructure.WithLogger(ctx, infrastructure.LoggerFrom(ctx, logger).With(
+				zap.Stringer("principal", principal.AccountUUID)))
+		}
+		if _, ok := app.PrincipalFrom(ctx); !ok && protectedMethods[info.FullMethod] {
+			return nil, statusError(ctx, logger, app.ErrUnauthenticated)
+		}
+		return handler(ctx, request)
+	}
+}
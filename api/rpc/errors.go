@@ -0,0 +1,63 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	r "github.com/freerware/tutor/api/representations"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// invalidArgumentErrors are the errors that indicate a malformed or invalid
+// request.
+var invalidArgumentErrors = []error{
+	r.ErrInvalidPageToken,
+	domain.ErrFutureCreatedAt,
+	domain.ErrFutureUpdatedAt,
+	domain.ErrInvalidUpdatedAt,
+	domain.ErrFutureDeletedAt,
+	domain.ErrInvalidDeletedAt,
+	domain.ErrNegativeLikes,
+	domain.ErrPostAlreadyPublished,
+	app.ErrInvalidPassword,
+}
+
+// statusError converts the provided error into the gRPC status that
+// describes it. Errors that clients cannot be told about are logged with the
+// logger of the call before being replaced with an opaque internal status.
+func statusError(ctx context.Context, logger *zap.Logger, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, app.ErrAccountNotFound),
+		errors.Is(err, app.ErrPostNotFound),
+		errors.Is(err, domain.ErrPostNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, app.ErrInvalidCredentials),
+		errors.Is(err, app.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, app.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, app.ErrUsernameTaken),
+		errors.Is(err, app.ErrPostUUIDTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	for _, invalid := range invalidArgumentErrors {
+		if errors.Is(err, invalid) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	infrastructure.LoggerFrom(ctx, logger).Error("failed to handle call", zap.Error(err))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// invalidArgument constructs the status describing an invalid request.
+func invalidArgument(format string, args ...any) error {
+	return status.Errorf(codes.InvalidArgument, format, args...)
+}
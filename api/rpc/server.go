@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/freerware/tutor/api/representations/protobuf/gen"
+	app "github.com/freerware/tutor/application"
+	"github.com/freerware/tutor/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+type ServerParameters struct {
+	fx.In
+
+	Configuration         config.Configuration
+	AccountService        app.AccountService
+	AuthenticationService app.AuthenticationService
+	Shutdowner            fx.Shutdowner
+	Logger                *zap.Logger
+}
+
+// Server serves the gRPC API, which exposes the same operations as the
+// HTTP API for clients that prefer typed calls.
+type Server struct {
+	host       string
+	port       int
+	grpcServer *grpc.Server
+	shutdowner fx.Shutdowner
+	logger     *zap.Logger
+}
+
+func New(parameters ServerParameters) Server {
+
+	serverConfig := parameters.Configuration.GRPC
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(authentication(parameters.AuthenticationService, parameters.Logger)),
+	)
+	gen.RegisterAccountServiceServer(grpcServer, &accountService{
+		service: parameters.AccountService,
+		logger:  parameters.Logger,
+	})
+
+	return Server{
+		host:       serverConfig.Host,
+		port:       serverConfig.Port,
+		grpcServer: grpcServer,
+		shutdowner: parameters.Shutdowner,
+		logger:     parameters.Logger,
+	}
+}
+
+// Start binds the address of the server, failing if it cannot, and then
+// serves calls in the background. Should serving fail later on, the
+// application is shut down with a non-zero exit code.
+func (s *Server) Start() error {
+	s.logger.Info(
+		"Starting gRPC server",
+		zap.String("host", s.host),
+		zap.Int("port", s.port),
+	)
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.host, s.port))
+	if err != nil {
+		return err
+	}
+	go func() {
+		err := s.grpcServer.Serve(listener)
+		if err == nil || errors.Is(err, grpc.ErrServerStopped) {
+			return
+		}
+		s.logger.Error("gRPC server failed", zap.Error(err))
+		if err := s.shutdowner.Shutdown(fx.ExitCode(1)); err != nil {
+			s.logger.Error("failed to shut down", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Stop stops the server once the in-flight calls complete, abandoning them
+// if the provided context expires first.
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping gRPC server")
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) Port() int {
+	return s.port
+}
+
+func (s *Server) Host() string {
+	return s.host
+}
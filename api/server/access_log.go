@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/freerware/tutor/infrastructure"
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// headerRequestID is the header that identifies a request.
+const headerRequestID = "X-Request-ID"
+
+// maximumRequestIDLength bounds the length of the request identifiers that
+// are accepted from clients.
+const maximumRequestIDLength = 128
+
+// NewAccessLog constructs the middleware that identifies each request and
+// logs it once it has been responded to. Requests are identified by their
+// X-Request-ID header, or by a generated identifier when they lack a valid
+// one, and the identifier is echoed in the response. The handlers receive a
+// logger that carries the identifier, along with the identifier of the trace
+// of the request, within the context of the request.
+func NewAccessLog(logger *zap.Logger) MiddlewareResult {
+	return MiddlewareResult{Middleware: Middleware{
+		Name: "accessLog",
+		Wrap: func(next http.Handler) http.Handler {
+			return accessLog(logger, next)
+		},
+	}}
+}
+
+func accessLog(logger *zap.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		id := request.Header.Get(headerRequestID)
+		if !validRequestID(id) {
+			id = uuid.Must(uuid.NewV4()).String()
+		}
+		w.Header().Set(headerRequestID, id)
+		requestLogger := logger.With(zap.String("requestID", id))
+		if span := trace.SpanContextFromContext(request.Context()); span.IsValid() {
+			requestLogger = requestLogger.With(zap.Stringer("traceID", span.TraceID()))
+		}
+		ctx := infrastructure.WithLogger(request.Context(), requestLogger)
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, request.WithContext(ctx))
+
+		var route string
+		if r := mux.CurrentRoute(request); r != nil {
+			route, _ = r.GetPathTemplate()
+		}
+		requestLogger.Info("served request",
+			zap.String("method", request.Method),
+			zap.String("route", route),
+			zap.String("path", request.URL.Path),
+			zap.Int("status", recorder.statusCode()),
+			zap.Int("bytes", recorder.bytes),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("mediaType", w.Header().Get("Content-Type")),
+			zap.String("encoding", w.Header().Get("Content-Encoding")),
+		)
+	})
+}
+
+// validRequestID indicates if the provided request identifier is one that
+// can be safely logged and echoed.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maximumRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// responseRecorder records the status and size of the response written
+// through it.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status int
+	bytes  int
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap provides the underlying response writer, so that the response can
+// still be flushed through an http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// statusCode provides the status of the response, which is implied when
+// nothing was written.
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
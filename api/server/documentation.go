@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/freerware/tutor/api/openapi"
+	"github.com/go-yaml/yaml"
+)
+
+var documentationInfo = openapi.Info{Title: "tutor", Version: "1.0.0"}
+
+// newDocumentation constructs the configuration serving the OpenAPI document
+// that describes the provided configurations, along with itself. The
+// document is derived from the same configurations the router is built from,
+// so it cannot disagree with the routes that are served.
+func newDocumentation(
+	configurations ...MuxConfiguration) (MuxConfiguration, error) {
+
+	var jsonDocument, yamlDocument []byte
+	getJSON := documentOperation("application/json")
+	getYAML := documentOperation("application/yaml")
+	documentation := MuxConfiguration{
+		PathPrefix: "",
+		Handlers: []HandlerConfiguration{
+			{
+				Path:        "/openapi.json",
+				HandlerFunc: serveDocument("application/json", &jsonDocument),
+				Methods:     []string{"GET"},
+				Operation:   getJSON,
+			},
+			{
+				Path:        "/openapi.yaml",
+				HandlerFunc: serveDocument("application/yaml", &yamlDocument),
+				Methods:     []string{"GET"},
+				Operation:   getYAML,
+			},
+		},
+	}
+
+	routes := []openapi.Route{}
+	for _, m := range append(configurations, documentation) {
+		for _, h := range m.Handlers {
+			for _, method := range h.Methods {
+				routes = append(routes, openapi.Route{
+					Method:    method,
+					Path:      m.PathPrefix + h.Path,
+					Operation: h.Operation,
+				})
+			}
+		}
+	}
+	document := openapi.New(documentationInfo, routes...)
+
+	var err error
+	if jsonDocument, err = json.MarshalIndent(document, "", "  "); err != nil {
+		return MuxConfiguration{}, err
+	}
+	if yamlDocument, err = yaml.Marshal(document); err != nil {
+		return MuxConfiguration{}, err
+	}
+	return documentation, nil
+}
+
+// documentOperation describes the retrieval of the OpenAPI document, which
+// is only ever served in the provided media type.
+func documentOperation(contentType string) openapi.Operation {
+	return openapi.Operation{
+		Summary: "Retrieve the OpenAPI document describing the API",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Content: &openapi.Content{
+				MediaTypes: []string{contentType},
+				Schema:     map[string]any{},
+			}},
+		},
+	}
+}
+
+// serveDocument serves the provided serialized document.
+func serveDocument(
+	contentType string, document *[]byte) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, request *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(*document)))
+		w.Write(*document)
+	}
+}
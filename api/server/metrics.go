@@ -0,0 +1,87 @@
+package server
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/uber-go/tally"
+)
+
+// unmatchedRoute is the route that requests matching no route are reported
+// under.
+const unmatchedRoute = "unmatched"
+
+// latencyBuckets are the buckets that request latencies are distributed
+// amongst. Their bounds are whole units, so that they name metrics cleanly.
+var latencyBuckets = tally.DurationBuckets{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// NewMetrics constructs the middleware that measures the requests served by
+// each route, tagged by the template of the route and the request method:
+// the latency of the requests, how many are in flight, how many end with
+// each class of status, and how many are served with each media type and
+// content coding.
+func NewMetrics(scope tally.Scope) MiddlewareResult {
+	m := &metrics{scope: scope.SubScope("http")}
+	return MiddlewareResult{Middleware: Middleware{
+		Name: "metrics",
+		Wrap: m.wrap,
+	}}
+}
+
+type metrics struct {
+	scope    tally.Scope
+	inFlight sync.Map
+}
+
+func (m *metrics) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		start := time.Now()
+		route := unmatchedRoute
+		if r := mux.CurrentRoute(request); r != nil {
+			route, _ = r.GetPathTemplate()
+		}
+		scope := m.scope.Tagged(map[string]string{"route": route, "method": request.Method})
+
+		// requests in flight are counted for each route and method.
+		counter, _ := m.inFlight.LoadOrStore(route+" "+request.Method, new(atomic.Int64))
+		inFlight := counter.(*atomic.Int64)
+		scope.Gauge("in_flight").Update(float64(inFlight.Add(1)))
+		defer func() {
+			scope.Gauge("in_flight").Update(float64(inFlight.Add(-1)))
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, request)
+
+		scope.Histogram("latency", latencyBuckets).RecordDuration(time.Since(start))
+		status := strconv.Itoa(recorder.statusCode()/100) + "xx"
+		scope.Tagged(map[string]string{"status": status}).Counter("requests").Inc(1)
+		if mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type")); err == nil {
+			scope.Tagged(map[string]string{"media_type": mediaType}).Counter("media_types").Inc(1)
+		}
+		if recorder.bytes > 0 {
+			encoding := w.Header().Get("Content-Encoding")
+			if encoding == "" {
+				encoding = "identity"
+			}
+			scope.Tagged(map[string]string{"encoding": encoding}).Counter("encodings").Inc(1)
+		}
+	})
+}
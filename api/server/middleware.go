@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+
+	"go.uber.org/fx"
+)
+
+// Middleware wraps handlers with behavior that cuts across them, such as
+// logging, authentication, rate limiting or metrics.
+//
+// Middlewares wrap one another in ascending order, so the middleware with
+// the lowest order sees each request first and each response last.
+// Middlewares of the same order are ordered by name, so that the order
+// does not depend on the order they are provided in.
+type Middleware struct {
+	Name  string
+	Order int
+	Wrap  func(http.Handler) http.Handler
+}
+
+// MiddlewareResult contributes a middleware that applies to every handler
+// of the server. Any module can provide one.
+type MiddlewareResult struct {
+	fx.Out
+
+	Middleware Middleware `group:"middlewares"`
+}
+
+// sortMiddlewares orders the provided middlewares in the order they wrap one
+// another.
+func sortMiddlewares(middlewares []Middleware) []Middleware {
+	sorted := append([]Middleware{}, middlewares...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Order != sorted[j].Order {
+			return sorted[i].Order < sorted[j].Order
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// chain wraps the provided handler with the provided middlewares, in the
+// order they are provided in. The first middleware is the outermost.
+func chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i].Wrap(handler)
+	}
+	return handler
+}
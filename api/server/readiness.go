@@ -0,0 +1,24 @@
+package server
+
+import "sync/atomic"
+
+// Readiness indicates whether the server is ready to handle requests. The
+// server stops being ready as soon as it begins to stop, so that load
+// balancers stop routing requests to it before it stops accepting them.
+type Readiness struct {
+	draining atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// Ready indicates whether the server is ready to handle requests.
+func (r *Readiness) Ready() bool {
+	return !r.draining.Load()
+}
+
+// drain marks the server as no longer ready to handle requests.
+func (r *Readiness) drain() {
+	r.draining.Store(true)
+}
@@ -3,10 +3,15 @@ package server
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"sort"
 	"text/tabwriter"
+	"time"
 
+	"github.com/freerware/tutor/api/openapi"
 	"github.com/freerware/tutor/config"
 	"github.com/gorilla/mux"
 	"go.uber.org/fx"
@@ -16,60 +21,120 @@ import (
 type MuxConfiguration struct {
 	PathPrefix string
 	Handlers   []HandlerConfiguration
+
+	// Middlewares wrap each of the handlers, within the middlewares of the
+	// server.
+	Middlewares []Middleware
 }
 
 type HandlerConfiguration struct {
 	Path        string
 	HandlerFunc func(http.ResponseWriter, *http.Request)
 	Methods     []string
+
+	// Middlewares wrap the handler, within the middlewares of the server and
+	// of its mux configuration.
+	Middlewares []Middleware
+
+	// Operation describes the handler within the OpenAPI document.
+	Operation openapi.Operation
 }
 
 type ServerParameters struct {
 	fx.In
 
-	Configuration    config.Configuration
-	MuxConfiguration MuxConfiguration
-	Logger           *zap.Logger
+	Configuration     config.Configuration
+	MuxConfigurations []MuxConfiguration `group:"muxConfigurations"`
+	Middlewares       []Middleware       `group:"middlewares"`
+	Readiness         *Readiness
+	Shutdowner        fx.Shutdowner
+	Logger            *zap.Logger
 }
 
 type Server struct {
-	host       string
-	port       int
-	httpServer http.Server
-	logger     *zap.Logger
+	host        string
+	port        int
+	drainPeriod time.Duration
+	httpServer  *http.Server
+	readiness   *Readiness
+	shutdowner  fx.Shutdowner
+	logger      *zap.Logger
 }
 
-func New(parameters ServerParameters) Server {
+func New(parameters ServerParameters) (Server, error) {
 
 	serverConfig := parameters.Configuration.Server
 
-	httpServer := http.Server{
-		Addr:    fmt.Sprintf("%s:%d", serverConfig.Host, serverConfig.Port),
-		Handler: newMux(parameters.Logger, parameters.MuxConfiguration),
+	documentation, err := newDocumentation(parameters.MuxConfigurations...)
+	if err != nil {
+		return Server{}, err
+	}
+	configurations := append(parameters.MuxConfigurations, documentation)
+
+	tlsConfig, err := newTLSConfig(serverConfig.TLS, parameters.Logger)
+	if err != nil {
+		return Server{}, err
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", serverConfig.Host, serverConfig.Port),
+		Handler:           newMux(parameters.Logger, parameters.Middlewares, configurations...),
+		TLSConfig:         tlsConfig,
+		ReadTimeout:       time.Duration(serverConfig.ReadTimeout) * time.Second,
+		ReadHeaderTimeout: time.Duration(serverConfig.ReadHeaderTimeout) * time.Second,
+		WriteTimeout:      time.Duration(serverConfig.WriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(serverConfig.IdleTimeout) * time.Second,
+		MaxHeaderBytes:    serverConfig.MaxHeaderBytes,
 	}
 
 	s := Server{
-		port:       serverConfig.Port,
-		host:       serverConfig.Host,
-		httpServer: httpServer,
-		logger:     parameters.Logger,
+		port:        serverConfig.Port,
+		host:        serverConfig.Host,
+		drainPeriod: time.Duration(serverConfig.DrainPeriod) * time.Second,
+		httpServer:  httpServer,
+		readiness:   parameters.Readiness,
+		shutdowner:  parameters.Shutdowner,
+		logger:      parameters.Logger,
 	}
 
-	return s
+	return s, nil
 }
 
-func newMux(logger *zap.Logger, m MuxConfiguration) *mux.Router {
+func newMux(
+	logger *zap.Logger,
+	middlewares []Middleware,
+	configurations ...MuxConfiguration) *mux.Router {
+
+	// register the most specific path prefixes first.
+	sort.SliceStable(configurations, func(i, j int) bool {
+		return len(configurations[i].PathPrefix) > len(configurations[j].PathPrefix)
+	})
 
+	// the middlewares of the server wrap those of each mux configuration,
+	// which wrap those of each handler.
+	middlewares = sortMiddlewares(middlewares)
 	r := mux.NewRouter()
-	sr := r.PathPrefix(m.PathPrefix).Subrouter()
-	for _, h := range m.Handlers {
-		sr.HandleFunc(h.Path, h.HandlerFunc).Methods(h.Methods...)
+	for _, m := range configurations {
+		sr := r.PathPrefix(m.PathPrefix).Subrouter()
+		muxMiddlewares := append(append([]Middleware{}, middlewares...), sortMiddlewares(m.Middlewares)...)
+		for _, h := range m.Handlers {
+			handlerMiddlewares := append(append([]Middleware{}, muxMiddlewares...), sortMiddlewares(h.Middlewares)...)
+			sr.Handle(h.Path, chain(http.HandlerFunc(h.HandlerFunc), handlerMiddlewares...)).Methods(h.Methods...)
+		}
+		printMux(logger, m)
 	}
-	printMux(logger, m)
 
+	// requests that match no route pass through the middlewares of the
+	// server as well.
+	r.NotFoundHandler = chain(http.NotFoundHandler(), middlewares...)
+	r.MethodNotAllowedHandler = chain(http.HandlerFunc(methodNotAllowed), middlewares...)
 	return r
 }
 
+func methodNotAllowed(w http.ResponseWriter, request *http.Request) {
+	w.WriteHeader(http.StatusMethodNotAllowed)
+}
+
 func printMux(logger *zap.Logger, m MuxConfiguration) {
 	logger.Info("~~~~~~~~~~ PATHS ~~~~~~~~~~")
 	for _, h := range m.Handlers {
@@ -84,16 +149,48 @@ func printMux(logger *zap.Logger, m MuxConfiguration) {
 	logger.Info("~~~~~~~~~~~~~~~~~~~~~~~~~~~")
 }
 
+// Start binds the address of the server, failing if it cannot, and then
+// serves requests in the background. Should serving fail later on, the
+// application is shut down with a non-zero exit code.
 func (s *Server) Start() error {
 	s.logger.Info(
 		"Starting HTTP server",
 		zap.String("host", s.host),
 		zap.Int("port", s.port),
+		zap.Bool("tls", s.httpServer.TLSConfig != nil),
 	)
-	return s.httpServer.ListenAndServe()
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		var err error
+		if s.httpServer.TLSConfig != nil {
+			err = s.httpServer.ServeTLS(listener, "", "")
+		} else {
+			err = s.httpServer.Serve(listener)
+		}
+		if errors.Is(err, http.ErrServerClosed) {
+			return
+		}
+		s.logger.Error("HTTP server failed", zap.Error(err))
+		if err := s.shutdowner.Shutdown(fx.ExitCode(1)); err != nil {
+			s.logger.Error("failed to shut down", zap.Error(err))
+		}
+	}()
+	return nil
 }
 
+// Stop reports that the server is no longer ready, giving load balancers
+// the drain period to stop routing requests to it, and then gracefully
+// shuts the server down.
 func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("Draining HTTP server", zap.Duration("drainPeriod", s.drainPeriod))
+	s.readiness.drain()
+	select {
+	case <-time.After(s.drainPeriod):
+	case <-ctx.Done():
+	}
 	s.logger.Info("Stopping HTTP server")
 	return s.httpServer.Shutdown(ctx)
 }
@@ -105,3 +202,8 @@ func (s *Server) Port() int {
 func (s *Server) Host() string {
 	return s.host
 }
+
+// Handler provides the handler that routes requests to the resources.
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/freerware/tutor/config"
+	"go.uber.org/zap"
+)
+
+// certificateCheckInterval bounds how often the certificate files are
+// checked for changes.
+const certificateCheckInterval = time.Second
+
+// tlsVersions associates the configurable minimum TLS versions with their
+// protocol versions.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// intermediateCipherSuites are the TLS 1.2 cipher suites of the Mozilla
+// intermediate configuration, which only offer forward secrecy with
+// authenticated encryption.
+var intermediateCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// newTLSConfig constructs the TLS configuration described by the provided
+// configuration, or nil when no certificate is provided. The certificate and
+// client CA bundle are reloaded from disk when they change, so that they can
+// be rotated without restarting the server.
+func newTLSConfig(c config.TLSConfiguration, logger *zap.Logger) (*tls.Config, error) {
+	if c.CertFile == "" && c.KeyFile == "" {
+		return nil, nil
+	}
+
+	minVersion, ok := tlsVersions[c.MinVersion]
+	if c.MinVersion == "" {
+		minVersion, ok = tls.VersionTLS12, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("server: unsupported minimum TLS version %q", c.MinVersion)
+	}
+	base := &tls.Config{MinVersion: minVersion}
+	switch c.CipherPolicy {
+	case "", "default":
+	case "intermediate":
+		base.CipherSuites = intermediateCipherSuites
+	case "modern":
+		base.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("server: unknown cipher policy %q", c.CipherPolicy)
+	}
+	if c.ClientCAFile != "" {
+		switch c.ClientAuth {
+		case "", "require":
+			base.ClientAuth = tls.RequireAndVerifyClientCert
+		case "optional":
+			base.ClientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("server: unknown client authentication %q", c.ClientAuth)
+		}
+	}
+
+	files := &certificateFiles{
+		certFile:     c.CertFile,
+		keyFile:      c.KeyFile,
+		clientCAFile: c.ClientCAFile,
+		logger:       logger,
+	}
+	if err := files.load(); err != nil {
+		return nil, err
+	}
+	base.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		certificate, _ := files.current()
+		return certificate, nil
+	}
+	if c.ClientCAFile != "" {
+		base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			_, clientCAs := files.current()
+			config := base.Clone()
+			config.GetConfigForClient = nil
+			config.ClientCAs = clientCAs
+			return config, nil
+		}
+	}
+	return base, nil
+}
+
+// certificateFiles holds the certificate and client CA bundle most recently
+// loaded from disk.
+type certificateFiles struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	logger       *zap.Logger
+
+	mu          sync.Mutex
+	checked     time.Time
+	modified    map[string]time.Time
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+}
+
+// current provides the certificate and client CA bundle, reloading them
+// first when their files have changed. Files that cannot be reloaded are
+// reported, and the previously loaded ones remain in use.
+func (f *certificateFiles) current() (*tls.Certificate, *x509.CertPool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if time.Since(f.checked) >= certificateCheckInterval {
+		f.checked = time.Now()
+		if f.changed() {
+			if err := f.reload(); err != nil {
+				f.logger.Error("failed to reload TLS certificates", zap.Error(err))
+			} else {
+				f.logger.Info("reloaded TLS certificates")
+			}
+		}
+	}
+	return f.certificate, f.clientCAs
+}
+
+// load loads the certificate and client CA bundle.
+func (f *certificateFiles) load() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checked = time.Now()
+	f.changed()
+	return f.reload()
+}
+
+// changed records the modification times of the files, indicating if any of
+// them differ from those previously recorded.
+func (f *certificateFiles) changed() bool {
+	modified := map[string]time.Time{}
+	changed := f.modified == nil
+	for _, name := range []string{f.certFile, f.keyFile, f.clientCAFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+		modified[name] = info.ModTime()
+		changed = changed || !info.ModTime().Equal(f.modified[name])
+	}
+	f.modified = modified
+	return changed
+}
+
+func (f *certificateFiles) reload() error {
+	certificate, err := tls.LoadX509KeyPair(f.certFile, f.keyFile)
+	if err != nil {
+		return err
+	}
+	var clientCAs *x509.CertPool
+	if f.clientCAFile != "" {
+		b, err := os.ReadFile(f.clientCAFile)
+		if err != nil {
+			return err
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(b) {
+			return fmt.Errorf("server: no certificates found within %s", f.clientCAFile)
+		}
+	}
+	f.certificate, f.clientCAs = &certificate, clientCAs
+	return nil
+}
+
+// ClientIdentity describes the verified certificate that a client presented
+// over mutual TLS.
+type ClientIdentity struct {
+	Subject        string
+	CommonName     string
+	DNSNames       []string
+	EmailAddresses []string
+	URIs           []string
+	SerialNumber   string
+}
+
+// ClientIdentityOf provides the identity of the verified certificate that
+// the client presented with the request, indicating if there is one.
+func ClientIdentityOf(request *http.Request) (ClientIdentity, bool) {
+	if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 {
+		return ClientIdentity{}, false
+	}
+	certificate := request.TLS.VerifiedChains[0][0]
+	identity := ClientIdentity{
+		Subject:        certificate.Subject.String(),
+		CommonName:     certificate.Subject.CommonName,
+		DNSNames:       certificate.DNSNames,
+		EmailAddresses: certificate.EmailAddresses,
+		SerialNumber:   certificate.SerialNumber.String(),
+	}
+	for _, uri := range certificate.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+	return identity, true
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies the spans started by the server.
+const instrumentationName = "github.com/freerware/tutor/api/server"
+
+// NewTracing constructs the middleware that describes each request with a
+// span, continuing the trace described by the W3C traceparent header of the
+// request, if any. It wraps every other middleware of the server, so that
+// they can refer to the span.
+func NewTracing(provider trace.TracerProvider) MiddlewareResult {
+	tracer := provider.Tracer(instrumentationName)
+	return MiddlewareResult{Middleware: Middleware{
+		Name:  "tracing",
+		Order: -1,
+		Wrap: func(next http.Handler) http.Handler {
+			return tracing(tracer, next)
+		},
+	}}
+}
+
+func tracing(tracer trace.Tracer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(
+			request.Context(), propagation.HeaderCarrier(request.Header))
+		name := request.Method
+		attributes := []attribute.KeyValue{
+			semconv.HTTPRequestMethodKey.String(request.Method),
+			semconv.URLPath(request.URL.Path),
+		}
+		if r := mux.CurrentRoute(request); r != nil {
+			if route, err := r.GetPathTemplate(); err == nil {
+				name += " " + route
+				attributes = append(attributes, semconv.HTTPRoute(route))
+			}
+		}
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attributes...))
+		defer span.End()
+
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, request.WithContext(ctx))
+
+		status := recorder.statusCode()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
@@ -6,85 +6,534 @@ import (
 
 	"github.com/freerware/tutor/domain"
 	"github.com/freerware/tutor/infrastructure"
-	"github.com/freerware/work/v4/unit"
 	u "github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
+// instrumentationName identifies the spans started by the application.
+const instrumentationName = "github.com/freerware/tutor/application"
+
 // AccountService encapsulates the various operations
 // our application offers for user accounts.
 type AccountService struct {
-	uniter  unit.Uniter
-	queryer infrastructure.Queryer
+	uniter     infrastructure.Uniter
+	queryer    infrastructure.Queryer
+	tracer     trace.Tracer
+	authorizer authorizer
 }
 
 type AccountServiceParameters struct {
 	fx.In
 
-	Uniter  unit.Uniter `name:"uniter"`
-	Queryer infrastructure.Queryer
+	Uniter         infrastructure.Uniter
+	Queryer        infrastructure.Queryer
+	TracerProvider trace.TracerProvider
+	Logger         *zap.Logger
 }
 
 func NewAccountService(
 	parameters AccountServiceParameters) AccountService {
 	return AccountService{
-		uniter:  parameters.Uniter,
-		queryer: parameters.Queryer,
+		uniter:     parameters.Uniter,
+		queryer:    parameters.Queryer,
+		tracer:     parameters.TracerProvider.Tracer(instrumentationName),
+		authorizer: newAuthorizer(parameters.Logger),
 	}
 }
 
-// Get retrieves an existing account.
-func (a *AccountService) Get(uuid u.UUID) (domain.Account, error) {
-	unit, err := a.uniter.Unit()
+// Get retrieves an existing account, withholding the drafts that the
+// principal did not author.
+func (a *AccountService) Get(ctx context.Context, uuid u.UUID) (_ domain.Account, err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.Get")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	unit, err := a.uniter.Unit(ctx)
 	if err != nil {
 		return domain.Account{}, err
 	}
-	repository := infrastructure.NewAccountRepository(unit, a.queryer)
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
 	account, err := repository.Get(uuid)
 	if err != nil {
 		return domain.Account{}, err
 	}
 	if account == nil {
-		return domain.Account{}, errors.New("application: account not found")
+		return domain.Account{}, ErrAccountNotFound
+	}
+	return a.authorizer.visible(ctx, "AccountService.Get", *account), nil
+}
+
+// List retrieves a page of existing accounts, along with the total number
+// of accounts, withholding the drafts that the principal did not author.
+func (a *AccountService) List(ctx context.Context, offset, limit int) (_ []domain.Account, _ int, err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.List")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	unit, err := a.uniter.Unit(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
+	accounts, total, err := repository.FindPage(a.queryer.QueryPage(ctx, offset, limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, account := range accounts {
+		accounts[i] = a.authorizer.visible(ctx, "AccountService.List", account)
 	}
-	return *account, nil
+	return accounts, total, nil
 }
 
-// Create creates a new account.
-func (a *AccountService) Create(ctx context.Context, account domain.Account) error {
-	unit, err := a.uniter.Unit()
+// Export retrieves every existing account that matches the provided filter,
+// one at a time, from a consistent snapshot. Posts are only retrieved when
+// requested, and drafts are withheld unless the principal authored them.
+func (a *AccountService) Export(
+	ctx context.Context,
+	filter infrastructure.AccountFilter,
+	posts bool,
+	export func(domain.Account) error) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.Export")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	unit, err := a.uniter.Unit(ctx)
 	if err != nil {
 		return err
 	}
-	repository := infrastructure.NewAccountRepository(unit, a.queryer)
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
+	return repository.Each(ctx, a.queryer.QueryCursor(filter, posts), func(account domain.Account) error {
+		return export(a.authorizer.visible(ctx, "AccountService.Export", account))
+	})
+}
+
+// The outcomes of importing an account.
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportResult describes the outcome of importing an account, along with
+// the reason it was skipped or failed.
+type ImportResult struct {
+	Outcome string
+	Err     error
+}
+
+// Import creates the provided accounts, saving them in batches of the
+// provided size with a unit of work for each batch. Accounts that already
+// exist are skipped. When atomic, every account is saved with a single unit
+// of work instead, and none are created if any of them fail, or if aborted
+// because records that never became accounts failed. Only admins may import
+// accounts, which are created without a password until one is set through
+// ChangePassword.
+func (a *AccountService) Import(
+	ctx context.Context,
+	accounts []domain.Account,
+	batchSize int,
+	atomic bool,
+	aborted bool) (_ []ImportResult, err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.Import", trace.WithAttributes(
+		attribute.Int("accounts", len(accounts)),
+		attribute.Bool("atomic", atomic)))
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	if err = a.authorizer.authorizeRole(ctx, "AccountService.Import", RoleAdmin); err != nil {
+		return nil, err
+	}
+	results := make([]ImportResult, len(accounts))
+	if atomic && aborted {
+		for i := range results {
+			results[i] = ImportResult{Outcome: ImportFailed, Err: ErrImportAborted}
+		}
+		return results, nil
+	}
+	if atomic || batchSize < 1 {
+		batchSize = len(accounts)
+	}
+	seen := map[u.UUID]bool{}
+	for start := 0; start < len(accounts); start += batchSize {
+		end := min(start+batchSize, len(accounts))
+		a.importBatch(ctx, accounts[start:end], results[start:end], seen, atomic)
+	}
+	return results, nil
+}
+
+// importBatch creates the provided accounts with a single unit of work,
+// recording the outcome of each within the corresponding result.
+func (a *AccountService) importBatch(
+	ctx context.Context,
+	accounts []domain.Account,
+	results []ImportResult,
+	seen map[u.UUID]bool,
+	atomic bool) {
+
+	// the accounts that would have been created fail along with the batch.
+	fail := func(err error) {
+		for i := range results {
+			if results[i].Outcome == ImportCreated {
+				results[i] = ImportResult{Outcome: ImportFailed, Err: err}
+			}
+		}
+	}
+	unit, err := a.uniter.Unit(ctx)
+	if err != nil {
+		for i := range results {
+			results[i] = ImportResult{Outcome: ImportFailed, Err: err}
+		}
+		return
+	}
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
+	failed := false
+	for i, account := range accounts {
+		if seen[account.UUID()] {
+			results[i] = ImportResult{Outcome: ImportSkipped, Err: ErrAccountAlreadyExists}
+			continue
+		}
+		seen[account.UUID()] = true
+		err := a.ensurePostUUIDsAvailable(ctx, account, nil)
+		if err == nil {
+			err = repository.Add(account)
+		}
+		switch {
+		case errors.Is(err, infrastructure.ErrAccountAlreadyExists):
+			results[i] = ImportResult{Outcome: ImportSkipped, Err: ErrAccountAlreadyExists}
+		case err != nil:
+			results[i] = ImportResult{Outcome: ImportFailed, Err: err}
+			failed = true
+		default:
+			results[i] = ImportResult{Outcome: ImportCreated}
+		}
+	}
+	if atomic && failed {
+		fail(ErrImportAborted)
+		return
+	}
+	if err := save(ctx, unit, a.queryer); err != nil {
+		fail(err)
+	}
+}
+
+// Create creates a new account, which authenticates with the provided
+// password unless it is empty.
+func (a *AccountService) Create(ctx context.Context, account domain.Account, password string) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.Create")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	if err = a.ensureUsernameAvailable(ctx, account); err != nil {
+		return err
+	}
+	if err = a.ensurePostUUIDsAvailable(ctx, account, nil); err != nil {
+		return err
+	}
+	unit, err := a.uniter.Unit(ctx)
+	if err != nil {
+		return err
+	}
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
 	if err = repository.Add(account); err != nil {
 		return err
 	}
-	return unit.Save(ctx)
+	if password != "" {
+		credential, err := newCredential(account.UUID(), password, account.CreatedAt())
+		if err != nil {
+			return err
+		}
+		if err = unit.Add(credential); err != nil {
+			return err
+		}
+	}
+	return save(ctx, unit, a.queryer)
 }
 
-// Put upserts an account.
-func (a *AccountService) Put(ctx context.Context, account domain.Account) error {
-	unit, err := a.uniter.Unit()
+// Precondition decides whether a change may be made to the current state of
+// an account, as the principal may see it, which is nil when the account
+// does not exist. Preconditions are evaluated within the unit of work that
+// makes the change.
+type Precondition func(current *domain.Account) error
+
+// Put upserts an account, provided that the principal may act upon it and
+// that the provided preconditions hold. Accounts that already exist retain
+// their creation time, and posts cannot be added with the UUID of a post
+// written by another account.
+func (a *AccountService) Put(
+	ctx context.Context, account domain.Account, preconditions ...Precondition) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.Put")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	if err = a.authorizer.authorize(ctx, "AccountService.Put", account.UUID()); err != nil {
+		return err
+	}
+	unit, err := a.uniter.Unit(ctx)
 	if err != nil {
 		return err
 	}
-	repository := infrastructure.NewAccountRepository(unit, a.queryer)
+	if err = a.ensureUsernameAvailable(ctx, account); err != nil {
+		return err
+	}
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
+	existing, err := repository.Get(account.UUID())
+	if err != nil {
+		return err
+	}
+	if err = a.check(ctx, "AccountService.Put", existing, preconditions...); err != nil {
+		return err
+	}
+	if err = a.ensurePostUUIDsAvailable(ctx, account, existing); err != nil {
+		return err
+	}
+	if existing != nil {
+		if err = account.SetCreatedAt(existing.CreatedAt()); err != nil {
+			return err
+		}
+
+		// drafts are kept when they were withheld from the principal, since
+		// the principal could not have included them.
+		if principal, _ := PrincipalFrom(ctx); principal.AccountUUID != account.UUID() {
+			for _, post := range existing.Posts() {
+				if post.IsDraft() && !account.HasPost(post) {
+					account.AddPost(post)
+				}
+			}
+		}
+	}
 	if err = repository.Put(account); err != nil {
 		return err
 	}
-	return unit.Save(ctx)
+	return save(ctx, unit, a.queryer)
 }
 
-// Delete deletes an existing account.
-func (a *AccountService) Delete(ctx context.Context, account domain.Account) error {
-	unit, err := a.uniter.Unit()
+// Delete deletes an existing account, provided that the principal may act
+// upon it and that the provided preconditions hold.
+func (a *AccountService) Delete(
+	ctx context.Context, accountUUID u.UUID, preconditions ...Precondition) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.Delete")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	if err = a.authorizer.authorize(ctx, "AccountService.Delete", accountUUID); err != nil {
+		return err
+	}
+	unit, err := a.uniter.Unit(ctx)
 	if err != nil {
 		return err
 	}
-	repository := infrastructure.NewAccountRepository(unit, a.queryer)
-	if err = repository.Remove(account); err != nil {
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
+	account, err := repository.Get(accountUUID)
+	if err != nil {
 		return err
 	}
-	return unit.Save(ctx)
+	if account == nil {
+		return ErrAccountNotFound
+	}
+	if err = a.check(ctx, "AccountService.Delete", account, preconditions...); err != nil {
+		return err
+	}
+	if err = repository.Remove(*account); err != nil {
+		return err
+	}
+
+	// the account can no longer authenticate.
+	if err = unit.Remove(infrastructure.Credential{AccountUUID: accountUUID}); err != nil {
+		return err
+	}
+	return save(ctx, unit, a.queryer)
+}
+
+// check evaluates the provided preconditions against the current state of
+// the account, as the principal may see it.
+func (a *AccountService) check(
+	ctx context.Context, action string, account *domain.Account, preconditions ...Precondition) error {
+	if len(preconditions) == 0 {
+		return nil
+	}
+	var current *domain.Account
+	if account != nil {
+		visible := a.authorizer.visible(ctx, action, *account)
+		current = &visible
+	}
+	for _, precondition := range preconditions {
+		if err := precondition(current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPost retrieves an existing post written by the provided account. Drafts
+// are only found by their author.
+func (a *AccountService) GetPost(ctx context.Context, accountUUID, postUUID u.UUID) (_ domain.Post, err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.GetPost")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	account, err := a.Get(ctx, accountUUID)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	post, ok := account.Post(postUUID)
+	if !ok {
+		return domain.Post{}, ErrPostNotFound
+	}
+	return post, nil
+}
+
+// AddPost adds a new post to an existing account.
+func (a *AccountService) AddPost(ctx context.Context, accountUUID u.UUID, post domain.Post) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.AddPost")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	return a.alter(ctx, "AccountService.AddPost", accountUUID, func(account *domain.Account) error {
+		account.AddPost(post)
+		return nil
+	})
+}
+
+// PutPost upserts a post for an existing account, provided that the
+// provided preconditions hold. Posts that already exist retain their creation
+// time, and posts cannot be created with the UUID of a post written by
+// another account.
+func (a *AccountService) PutPost(
+	ctx context.Context, accountUUID u.UUID, post domain.Post, preconditions ...Precondition) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.PutPost")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	return a.alter(ctx, "AccountService.PutPost", accountUUID, func(account *domain.Account) error {
+		for _, precondition := range preconditions {
+			if err := precondition(account); err != nil {
+				return err
+			}
+		}
+		if existing, ok := account.Post(post.UUID()); ok {
+			if err := post.SetCreatedAt(existing.CreatedAt()); err != nil {
+				return err
+			}
+			return account.ReplacePost(post)
+		}
+		account.AddPost(post)
+		return nil
+	})
+}
+
+// DeletePost deletes an existing post from an existing account, provided
+// that the provided preconditions hold.
+func (a *AccountService) DeletePost(
+	ctx context.Context, accountUUID, postUUID u.UUID, preconditions ...Precondition) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.DeletePost")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	return a.alter(ctx, "AccountService.DeletePost", accountUUID, func(account *domain.Account) error {
+		for _, precondition := range preconditions {
+			if err := precondition(account); err != nil {
+				return err
+			}
+		}
+		if err := account.RemovePost(postUUID); err != nil {
+			return ErrPostNotFound
+		}
+		return nil
+	})
+}
+
+// Alter applies the provided modification to an existing account and saves
+// the account, provided that the principal may act upon it.
+func (a *AccountService) Alter(
+	ctx context.Context, accountUUID u.UUID, alter func(*domain.Account) error) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.Alter")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	return a.alter(ctx, "AccountService.Alter", accountUUID, alter)
+}
+
+// alter applies the provided modification to an existing account and saves
+// the account, authorizing it as the provided action. The modification is
+// given the account as the principal may see it, and cannot add posts with
+// the UUID of a post written by another account.
+func (a *AccountService) alter(
+	ctx context.Context, action string, accountUUID u.UUID, alter func(*domain.Account) error) error {
+	if err := a.authorizer.authorize(ctx, action, accountUUID); err != nil {
+		return err
+	}
+	unit, err := a.uniter.Unit(ctx)
+	if err != nil {
+		return err
+	}
+	repository := infrastructure.NewAccountRepository(ctx, unit, a.queryer)
+	account, err := repository.Get(accountUUID)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return ErrAccountNotFound
+	}
+
+	// the modification applies to the account as the principal sees it, and
+	// the drafts withheld from the principal are kept.
+	altered := a.authorizer.visible(ctx, action, *account)
+	withheld := []domain.Post{}
+	for _, post := range account.Posts() {
+		if !altered.HasPost(post) {
+			withheld = append(withheld, post)
+		}
+	}
+	if err = alter(&altered); err != nil {
+		return err
+	}
+	for _, post := range withheld {
+		if !altered.HasPost(post) {
+			altered.AddPost(post)
+		}
+	}
+	if altered.Username() != account.Username() {
+		if err = a.ensureUsernameAvailable(ctx, altered); err != nil {
+			return err
+		}
+	}
+	if err = a.ensurePostUUIDsAvailable(ctx, altered, account); err != nil {
+		return err
+	}
+	if err = repository.Put(altered); err != nil {
+		return err
+	}
+	return save(ctx, unit, a.queryer)
+}
+
+// ensureUsernameAvailable ensures that no other account has the username of
+// the provided account, so that the username identifies a single account
+// when authenticating. The unique index on usernames settles the races this
+// check cannot.
+func (a *AccountService) ensureUsernameAvailable(ctx context.Context, account domain.Account) error {
+	if account.Username() == "" {
+		return nil
+	}
+	credentials, err := a.queryer.QueryCredentials(ctx, account.Username()).Execute()
+	if err != nil {
+		return err
+	}
+	for _, credential := range credentials {
+		if credential.AccountUUID != account.UUID() {
+			return ErrUsernameTaken
+		}
+	}
+	return nil
+}
+
+// ensurePostUUIDsAvailable ensures that none of the posts of the provided
+// account that the existing account does not already have were written by
+// another account, since a post UUID identifies a single post across every
+// account.
+func (a *AccountService) ensurePostUUIDsAvailable(
+	ctx context.Context, account domain.Account, existing *domain.Account) error {
+	for _, post := range account.Posts() {
+		if existing != nil && existing.HasPost(post) {
+			continue
+		}
+		author, err := a.queryer.QueryPostAuthor(ctx, post.UUID()).Execute()
+		if err != nil {
+			return err
+		}
+		if author != nil && *author != account.UUID() {
+			return ErrPostUUIDTaken
+		}
+	}
+	return nil
 }
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
+	u "github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+func (q queryer) Query(_ context.Context, uuid u.UUID) infrastructure.AccountQuery {
+	matched := accountQuery{}
+	for _, account := range q.accounts {
+		if account.UUID() == uuid {
+			matched = append(matched, account)
+		}
+	}
+	return matched
+}
+
+func (q queryer) QueryPostAuthor(_ context.Context, postUUID u.UUID) infrastructure.PostAuthorQuery {
+	for _, account := range q.accounts {
+		if _, ok := account.Post(postUUID); ok {
+			author := account.UUID()
+			return postAuthorQuery{author: &author}
+		}
+	}
+	return postAuthorQuery{}
+}
+
+type accountQuery []domain.Account
+
+func (q accountQuery) Execute() ([]domain.Account, error) { return q, nil }
+
+type postAuthorQuery struct {
+	author *u.UUID
+}
+
+func (q postAuthorQuery) Execute() (*u.UUID, error) { return q.author, nil }
+
+// newAccountService constructs the service for the provided accounts, along
+// with the mapper that records the accounts it saves.
+func newAccountService(accounts ...domain.Account) (AccountService, *dataMapper) {
+	dm := &dataMapper{}
+	a := NewAccountService(AccountServiceParameters{
+		Uniter:         newUniter(dm),
+		Queryer:        queryer{accounts: accounts},
+		TracerProvider: noop.NewTracerProvider(),
+		Logger:         zap.NewNop(),
+	})
+	return a, dm
+}
+
+// newAccount constructs an account with the provided posts.
+func newAccount(uuid u.UUID, username string, posts ...u.UUID) domain.Account {
+	now := time.Now()
+	account := domain.ReconstituteAccount(domain.AccountParameters{
+		UUID:      uuid,
+		Username:  username,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	for _, post := range posts {
+		account.AddPost(domain.ReconstitutePost(domain.PostParameters{
+			UUID:       post,
+			Title:      "Title",
+			AuthorUUID: account.UUID(),
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}))
+	}
+	return account
+}
+
+func TestAccountServicePostUUIDs(t *testing.T) {
+	taken, own, fresh := u.Must(u.NewV4()), u.Must(u.NewV4()), u.Must(u.NewV4())
+	other := newAccount(u.Must(u.NewV4()), "other", taken)
+	existing := newAccount(u.Must(u.NewV4()), "jdoe", own)
+	admin := WithPrincipal(context.Background(), Principal{AccountUUID: u.Must(u.NewV4()), Role: RoleAdmin})
+
+	// with constructs the existing account with the provided posts instead.
+	with := func(posts ...u.UUID) domain.Account {
+		return newAccount(existing.UUID(), existing.Username(), posts...)
+	}
+	// post constructs a post of the existing account with the provided UUID.
+	post := func(uuid u.UUID) domain.Post {
+		post, _ := with(uuid).Post(uuid)
+		return post
+	}
+
+	tests := []struct {
+		name   string
+		change func(AccountService) error
+		err    error
+	}{
+		{
+			name: "create with the post of another account",
+			change: func(a AccountService) error {
+				return a.Create(admin, newAccount(u.Must(u.NewV4()), "jane", taken), "")
+			},
+			err: ErrPostUUIDTaken,
+		},
+		{
+			name: "create with a new post",
+			change: func(a AccountService) error {
+				return a.Create(admin, newAccount(u.Must(u.NewV4()), "jane", fresh), "")
+			},
+		},
+		{
+			name: "put with the post of another account",
+			change: func(a AccountService) error {
+				return a.Put(admin, with(own, taken))
+			},
+			err: ErrPostUUIDTaken,
+		},
+		{
+			name: "put with its own post",
+			change: func(a AccountService) error {
+				return a.Put(admin, with(own, fresh))
+			},
+		},
+		{
+			name: "put post with the UUID of the post of another account",
+			change: func(a AccountService) error {
+				return a.PutPost(admin, existing.UUID(), post(taken))
+			},
+			err: ErrPostUUIDTaken,
+		},
+		{
+			name: "put post with its own post",
+			change: func(a AccountService) error {
+				return a.PutPost(admin, existing.UUID(), post(own))
+			},
+		},
+		{
+			name: "import with the post of another account",
+			change: func(a AccountService) error {
+				results, err := a.Import(admin, []domain.Account{newAccount(u.Must(u.NewV4()), "jane", taken)}, 1, false, false)
+				if err != nil {
+					return err
+				}
+				return results[0].Err
+			},
+			err: ErrPostUUIDTaken,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, dm := newAccountService(other, existing)
+
+			err := test.change(a)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			saved := len(dm.inserted) + len(dm.updated)
+			if (saved == 0) != (test.err != nil) {
+				t.Fatalf("expected saving only without an error, got %d saved", saved)
+			}
+		})
+	}
+}
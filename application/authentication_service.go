@@ -0,0 +1,305 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/freerware/tutor/config"
+	"github.com/freerware/tutor/infrastructure"
+	u "github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// tokenIssuer identifies the bearer tokens issued by the application.
+const tokenIssuer = "tutor"
+
+// The bounds on the length of passwords, in characters.
+const (
+	minimumPasswordLength = 8
+	maximumPasswordLength = 128
+)
+
+// minimumTokenKeyLength is the minimum length, in bytes, of the key that
+// bearer tokens are signed with.
+const minimumTokenKeyLength = 32
+
+// Token is a bearer token that authenticates the principal it was issued to
+// until it expires.
+type Token struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// tokenClaims are the claims of a bearer token, whose subject is the UUID
+// of the account it was issued to.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+
+	Username string `json:"username"`
+}
+
+// AuthenticationService authenticates the principals that requests are made
+// on behalf of, and manages the passwords they authenticate with.
+type AuthenticationService struct {
+	uniter     infrastructure.Uniter
+	queryer    infrastructure.Queryer
+	tracer     trace.Tracer
+	key        []byte
+	ttl        time.Duration
+	authorizer authorizer
+
+	// decoy is verified against when no password is stored for a username,
+	// so that unknown usernames take as long to reject as known ones.
+	decoy string
+}
+
+type AuthenticationServiceParameters struct {
+	fx.In
+
+	Uniter         infrastructure.Uniter
+	Queryer        infrastructure.Queryer
+	TracerProvider trace.TracerProvider
+	Configuration  config.Configuration
+	Logger         *zap.Logger
+}
+
+func NewAuthenticationService(
+	parameters AuthenticationServiceParameters) (AuthenticationService, error) {
+	c := parameters.Configuration.Authentication
+	if len(c.TokenKey) < minimumTokenKeyLength {
+		return AuthenticationService{}, fmt.Errorf(
+			"application: token key must be at least %d bytes", minimumTokenKeyLength)
+	}
+	if c.TokenTTL <= 0 {
+		return AuthenticationService{}, fmt.Errorf("application: token TTL must be positive")
+	}
+	decoy, err := infrastructure.HashPassword(u.Must(u.NewV4()).String())
+	if err != nil {
+		return AuthenticationService{}, err
+	}
+	return AuthenticationService{
+		uniter:     parameters.Uniter,
+		queryer:    parameters.Queryer,
+		tracer:     parameters.TracerProvider.Tracer(instrumentationName),
+		key:        []byte(c.TokenKey),
+		ttl:        time.Duration(c.TokenTTL) * time.Second,
+		decoy:      decoy,
+		authorizer: newAuthorizer(parameters.Logger),
+	}, nil
+}
+
+// Identify authenticates the principal described by the provided value of
+// an Authorization header, which carries either Basic credentials or a
+// bearer token.
+func (a *AuthenticationService) Identify(ctx context.Context, authorization string) (Principal, error) {
+	scheme, credentials, _ := strings.Cut(authorization, " ")
+	credentials = strings.TrimSpace(credentials)
+	switch {
+	case strings.EqualFold(scheme, "Basic"):
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return Principal{}, ErrInvalidCredentials
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return Principal{}, ErrInvalidCredentials
+		}
+		return a.Authenticate(ctx, username, password)
+	case strings.EqualFold(scheme, "Bearer"):
+		return a.Verify(ctx, credentials)
+	}
+	return Principal{}, ErrInvalidCredentials
+}
+
+// Authenticate authenticates the account with the provided username and
+// password. Accounts that share a username with the account do not take part,
+// unless they have a password of their own.
+func (a *AuthenticationService) Authenticate(
+	ctx context.Context, username, password string) (_ Principal, err error) {
+	ctx, span := a.tracer.Start(ctx, "AuthenticationService.Authenticate")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	credentials, err := a.queryer.QueryCredentials(ctx, username).Execute()
+	if err != nil {
+		return Principal{}, err
+	}
+	var candidates []infrastructure.Credential
+	for _, credential := range credentials {
+		if credential.HasPassword() {
+			candidates = append(candidates, credential)
+		}
+	}
+	hash := a.decoy
+	if len(candidates) == 1 {
+		hash = candidates[0].PasswordHash
+	}
+	verified, err := infrastructure.VerifyPassword(hash, password)
+	if err != nil {
+		return Principal{}, err
+	}
+	if !verified || len(candidates) != 1 {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return Principal{
+		AccountUUID:     candidates[0].AccountUUID,
+		Username:        candidates[0].Username,
+		AuthenticatedBy: AuthenticatedWithPassword,
+		Role:            candidates[0].Role,
+	}, nil
+}
+
+// Issue issues a bearer token to the provided principal.
+func (a *AuthenticationService) Issue(principal Principal) (Token, error) {
+	now := time.Now()
+	expiresAt := now.Add(a.ttl)
+	claims := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   principal.AccountUUID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        u.Must(u.NewV4()).String(),
+		},
+		Username: principal.Username,
+	}
+	value, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.key)
+	if err != nil {
+		return Token{}, err
+	}
+	return Token{Value: value, ExpiresAt: expiresAt.Truncate(time.Second)}, nil
+}
+
+// Verify authenticates the principal that the provided bearer token was
+// issued to. Tokens issued before the password of the account last changed
+// are no longer valid, allowing a second for the precision that the times
+// are recorded with.
+func (a *AuthenticationService) Verify(ctx context.Context, token string) (_ Principal, err error) {
+	ctx, span := a.tracer.Start(ctx, "AuthenticationService.Verify")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	claims := tokenClaims{}
+	_, err = jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return a.key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+	accountUUID, err := u.FromString(claims.Subject)
+	if err != nil || claims.IssuedAt == nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+	credentials, err := a.queryer.QueryCredential(ctx, accountUUID).Execute()
+	if err != nil {
+		return Principal{}, err
+	}
+	if len(credentials) == 0 || !credentials[0].HasPassword() ||
+		claims.IssuedAt.Time.Add(time.Second).Before(credentials[0].UpdatedAt) {
+		return Principal{}, ErrInvalidCredentials
+	}
+	return Principal{
+		AccountUUID:     accountUUID,
+		Username:        credentials[0].Username,
+		AuthenticatedBy: AuthenticatedWithToken,
+		Role:            credentials[0].Role,
+	}, nil
+}
+
+// ChangePassword replaces the password of an existing account, provided
+// that the principal may act upon the account and that the current password
+// is the one that the account authenticates with. Accounts without a
+// password, such as those created without one, have their first password
+// set without a current one.
+func (a *AuthenticationService) ChangePassword(
+	ctx context.Context, accountUUID u.UUID, current, password string) (err error) {
+	ctx, span := a.tracer.Start(ctx, "AuthenticationService.ChangePassword")
+	defer func() { infrastructure.EndSpan(span, err) }()
+
+	if err = a.authorizer.authorize(ctx, "AuthenticationService.ChangePassword", accountUUID); err != nil {
+		return err
+	}
+	if err = validatePassword(password); err != nil {
+		return err
+	}
+	credentials, err := a.queryer.QueryCredential(ctx, accountUUID).Execute()
+	if err != nil {
+		return err
+	}
+	if len(credentials) == 0 {
+		return ErrAccountNotFound
+	}
+	credential := credentials[0]
+	if credential.HasPassword() {
+		verified, err := infrastructure.VerifyPassword(credential.PasswordHash, current)
+		if err != nil {
+			return err
+		}
+		if !verified {
+			return ErrIncorrectPassword
+		}
+	}
+	unit, err := a.uniter.Unit(ctx)
+	if err != nil {
+		return err
+	}
+
+	// accounts created without a password have no credential to replace.
+	now := time.Now()
+	if credential.CreatedAt.IsZero() {
+		created, err := newCredential(accountUUID, password, now)
+		if err != nil {
+			return err
+		}
+		if err = unit.Add(created); err != nil {
+			return err
+		}
+		return save(ctx, unit, a.queryer)
+	}
+	if credential.PasswordHash, err = infrastructure.HashPassword(password); err != nil {
+		return err
+	}
+	credential.UpdatedAt = now
+	if err = unit.Alter(credential); err != nil {
+		return err
+	}
+	return save(ctx, unit, a.queryer)
+}
+
+// newCredential constructs the credential that the provided account
+// authenticates with the provided password.
+func newCredential(accountUUID u.UUID, password string, now time.Time) (infrastructure.Credential, error) {
+	if err := validatePassword(password); err != nil {
+		return infrastructure.Credential{}, err
+	}
+	hash, err := infrastructure.HashPassword(password)
+	if err != nil {
+		return infrastructure.Credential{}, err
+	}
+	return infrastructure.Credential{
+		AccountUUID:  accountUUID,
+		PasswordHash: hash,
+		Role:         RoleMember,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// validatePassword ensures that the provided password is of a suitable
+// length.
+func validatePassword(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < minimumPasswordLength || length > maximumPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
@@ -0,0 +1,454 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/freerware/tutor/config"
+	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
+	"github.com/freerware/work/v4/unit"
+	u "github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+// queryer retrieves the provided accounts and credentials.
+type queryer struct {
+	infrastructure.Queryer
+
+	accounts    []domain.Account
+	credentials []infrastructure.Credential
+}
+
+func (q queryer) QueryCredential(_ context.Context, accountUUID u.UUID) infrastructure.CredentialQuery {
+	return q.matching(func(c infrastructure.Credential) bool { return c.AccountUUID == accountUUID })
+}
+
+func (q queryer) QueryCredentials(_ context.Context, username string) infrastructure.CredentialQuery {
+	return q.matching(func(c infrastructure.Credential) bool { return c.Username == username })
+}
+
+func (q queryer) matching(match func(infrastructure.Credential) bool) credentialQuery {
+	matched := credentialQuery{}
+	for _, credential := range q.credentials {
+		if match(credential) {
+			matched = append(matched, credential)
+		}
+	}
+	return matched
+}
+
+type credentialQuery []infrastructure.Credential
+
+func (q credentialQuery) Execute() ([]infrastructure.Credential, error) { return q, nil }
+
+// dataMapper records the entities that are saved.
+type dataMapper struct {
+	inserted []any
+	updated  []any
+}
+
+func (dm *dataMapper) Insert(_ context.Context, _ unit.MapperContext, entities ...any) error {
+	dm.inserted = append(dm.inserted, entities...)
+	return nil
+}
+
+func (dm *dataMapper) Update(_ context.Context, _ unit.MapperContext, entities ...any) error {
+	dm.updated = append(dm.updated, entities...)
+	return nil
+}
+
+func (dm *dataMapper) Delete(context.Context, unit.MapperContext, ...any) error { return nil }
+
+// newUniter constructs units of work that save with the provided mapper.
+func newUniter(dm *dataMapper) infrastructure.Uniter {
+	return infrastructure.NewUniter(infrastructure.UniterParameters{
+		Options: []unit.Option{unit.DataMappers(map[unit.TypeName]unit.DataMapper{
+			unit.TypeNameOf(domain.Account{}):                   dm,
+			unit.TypeNameOf(infrastructure.Credential{}):        dm,
+			unit.TypeNameOf(infrastructure.IdempotencyRecord{}): dm,
+		})},
+		TracerProvider: noop.NewTracerProvider(),
+	})
+}
+
+// newAuthenticationService constructs the service authenticating the
+// accounts with the provided credentials, along with the mapper that records
+// the credentials it saves.
+func newAuthenticationService(
+	t *testing.T, credentials ...infrastructure.Credential) (AuthenticationService, *dataMapper) {
+	dm := &dataMapper{}
+	c := config.Configuration{}
+	c.Authentication.TokenKey = strings.Repeat("k", minimumTokenKeyLength)
+	c.Authentication.TokenTTL = 3600
+	a, err := NewAuthenticationService(AuthenticationServiceParameters{
+		Uniter:         newUniter(dm),
+		Queryer:        queryer{credentials: credentials},
+		TracerProvider: noop.NewTracerProvider(),
+		Configuration:  c,
+		Logger:         zap.NewNop(),
+	})
+	if err != nil {
+		t.Fatalf("failed to construct the authentication service: %v", err)
+	}
+	return a, dm
+}
+
+// newPasswordCredential constructs the credential of an account that
+// authenticates with the provided password.
+func newPasswordCredential(t *testing.T, username, password string, updatedAt time.Time) infrastructure.Credential {
+	hash, err := infrastructure.HashPassword(password)
+	if err != nil {
+		t.Fatalf("failed to hash the password: %v", err)
+	}
+	return infrastructure.Credential{
+		AccountUUID:  u.Must(u.NewV4()),
+		Username:     username,
+		PasswordHash: hash,
+		Role:         RoleMember,
+		CreatedAt:    updatedAt,
+		UpdatedAt:    updatedAt,
+	}
+}
+
+// basic constructs the authorization of the provided username and password.
+func basic(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// sign constructs a bearer token with the provided claims, signed with the
+// key of the service constructed by newAuthenticationService.
+func sign(t *testing.T, claims tokenClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).
+		SignedString([]byte(strings.Repeat("k", minimumTokenKeyLength)))
+	if err != nil {
+		t.Fatalf("failed to sign the token: %v", err)
+	}
+	return token
+}
+
+// claims constructs the claims of a token issued to the provided account at
+// the provided time, expiring after the provided duration.
+func claims(accountUUID u.UUID, issuedAt time.Time, ttl time.Duration) tokenClaims {
+	return tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   accountUUID.String(),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(ttl)),
+		},
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	const password = "correct-horse-battery"
+	now := time.Now()
+	owner := newPasswordCredential(t, "jdoe", password, now.Add(-time.Hour))
+	changed := newPasswordCredential(t, "jane", password, now.Add(-time.Minute))
+	withoutPassword := infrastructure.Credential{
+		AccountUUID: u.Must(u.NewV4()),
+		Username:    "reset",
+		Role:        RoleMember,
+		CreatedAt:   now.Add(-time.Hour),
+		UpdatedAt:   now.Add(-time.Hour),
+	}
+	forged := claims(owner.AccountUUID, now, time.Hour)
+	forged.Issuer = "other"
+	valid := sign(t, claims(owner.AccountUUID, now, time.Hour))
+
+	tests := []struct {
+		name          string
+		authorization string
+		principal     u.UUID
+		by            string
+		err           error
+	}{
+		{
+			name:          "basic",
+			authorization: basic(owner.Username, password),
+			principal:     owner.AccountUUID,
+			by:            AuthenticatedWithPassword,
+		},
+		{
+			name:          "basic in lower case",
+			authorization: strings.Replace(basic(owner.Username, password), "Basic", "basic", 1),
+			principal:     owner.AccountUUID,
+			by:            AuthenticatedWithPassword,
+		},
+		{
+			name:          "basic with an incorrect password",
+			authorization: basic(owner.Username, "staple-battery-horse"),
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "basic with malformed credentials",
+			authorization: "Basic not-base64!",
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "basic without a colon",
+			authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte(owner.Username)),
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "basic without credentials",
+			authorization: "Basic",
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "bearer",
+			authorization: "Bearer " + valid,
+			principal:     owner.AccountUUID,
+			by:            AuthenticatedWithToken,
+		},
+		{
+			name:          "bearer in lower case",
+			authorization: "bearer " + valid,
+			principal:     owner.AccountUUID,
+			by:            AuthenticatedWithToken,
+		},
+		{
+			name:          "bearer with a malformed token",
+			authorization: "Bearer token",
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "bearer with an expired token",
+			authorization: "Bearer " + sign(t, claims(owner.AccountUUID, now.Add(-2*time.Hour), time.Hour)),
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "bearer with a token of another issuer",
+			authorization: "Bearer " + sign(t, forged),
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "bearer with a token issued before the password changed",
+			authorization: "Bearer " + sign(t, claims(changed.AccountUUID, now.Add(-time.Hour), 2*time.Hour)),
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "bearer with a token issued within a second of the password changing",
+			authorization: "Bearer " + sign(t, claims(changed.AccountUUID, changed.UpdatedAt.Add(-time.Second/2), time.Hour)),
+			principal:     changed.AccountUUID,
+			by:            AuthenticatedWithToken,
+		},
+		{
+			name:          "bearer with a token of an account without a password",
+			authorization: "Bearer " + sign(t, claims(withoutPassword.AccountUUID, now, time.Hour)),
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "bearer with a token of an unknown account",
+			authorization: "Bearer " + sign(t, claims(u.Must(u.NewV4()), now, time.Hour)),
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name:          "unknown scheme",
+			authorization: "Digest " + valid,
+			err:           ErrInvalidCredentials,
+		},
+		{
+			name: "no authorization",
+			err:  ErrInvalidCredentials,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, _ := newAuthenticationService(t, owner, changed, withoutPassword)
+
+			principal, err := a.Identify(context.Background(), test.authorization)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			if principal.AccountUUID != test.principal || principal.AuthenticatedBy != test.by {
+				t.Fatalf("expected %s authenticated with %q, got %+v", test.principal, test.by, principal)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	const password = "correct-horse-battery"
+	now := time.Now()
+	withoutPassword := infrastructure.Credential{
+		AccountUUID: u.Must(u.NewV4()),
+		Username:    "jdoe",
+		Role:        RoleMember,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	uncredentialed := infrastructure.Credential{AccountUUID: u.Must(u.NewV4()), Username: "jdoe"}
+	owner := newPasswordCredential(t, "jdoe", password, now)
+	duplicate := newPasswordCredential(t, "jdoe", password, now)
+
+	tests := []struct {
+		name        string
+		credentials []infrastructure.Credential
+		username    string
+		principal   u.UUID
+		err         error
+	}{
+		{
+			name:        "password",
+			credentials: []infrastructure.Credential{owner},
+			username:    owner.Username,
+			principal:   owner.AccountUUID,
+		},
+		{
+			name:        "password shared with accounts without one",
+			credentials: []infrastructure.Credential{withoutPassword, owner, uncredentialed},
+			username:    owner.Username,
+			principal:   owner.AccountUUID,
+		},
+		{
+			name:     "unknown username",
+			username: "unknown",
+			err:      ErrInvalidCredentials,
+		},
+		{
+			name:        "account without a password",
+			credentials: []infrastructure.Credential{withoutPassword},
+			username:    withoutPassword.Username,
+			err:         ErrInvalidCredentials,
+		},
+		{
+			name:        "account without a credential",
+			credentials: []infrastructure.Credential{uncredentialed},
+			username:    uncredentialed.Username,
+			err:         ErrInvalidCredentials,
+		},
+		{
+			name:        "accounts sharing a username and a password",
+			credentials: []infrastructure.Credential{owner, duplicate},
+			username:    owner.Username,
+			err:         ErrInvalidCredentials,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, _ := newAuthenticationService(t, test.credentials...)
+
+			// without a single candidate, the password is verified against the
+			// decoy, which fails with invalid credentials rather than an error
+			// of its own.
+			principal, err := a.Authenticate(context.Background(), test.username, password)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			if principal.AccountUUID != test.principal {
+				t.Fatalf("expected %s, got %s", test.principal, principal.AccountUUID)
+			}
+		})
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	const (
+		current  = "correct-horse-battery"
+		password = "staple-battery-horse"
+	)
+	owner := newPasswordCredential(t, "jdoe", current, time.Now().Add(-time.Hour))
+	uncredentialed := infrastructure.Credential{AccountUUID: u.Must(u.NewV4()), Username: "imported"}
+	withoutPassword := infrastructure.Credential{
+		AccountUUID: u.Must(u.NewV4()),
+		Username:    "reset",
+		Role:        RoleMember,
+		CreatedAt:   time.Now().Add(-time.Hour),
+		UpdatedAt:   time.Now().Add(-time.Hour),
+	}
+	admin := Principal{AccountUUID: u.Must(u.NewV4()), Role: RoleAdmin}
+	member := Principal{AccountUUID: u.Must(u.NewV4()), Role: RoleMember}
+
+	tests := []struct {
+		name       string
+		principal  *Principal
+		credential infrastructure.Credential
+		current    string
+		err        error
+		inserted   bool
+	}{
+		{
+			name:       "owner with the current password",
+			principal:  &Principal{AccountUUID: owner.AccountUUID, Role: RoleMember},
+			credential: owner,
+			current:    current,
+		},
+		{
+			name:       "owner with an incorrect password",
+			principal:  &Principal{AccountUUID: owner.AccountUUID, Role: RoleMember},
+			credential: owner,
+			current:    password,
+			err:        ErrIncorrectPassword,
+		},
+		{
+			name:       "admin without the current password",
+			principal:  &admin,
+			credential: owner,
+			err:        ErrIncorrectPassword,
+		},
+		{
+			name:       "admin setting the first password",
+			principal:  &admin,
+			credential: uncredentialed,
+			inserted:   true,
+		},
+		{
+			name:       "admin setting the first password of a credential",
+			principal:  &admin,
+			credential: withoutPassword,
+		},
+		{
+			name:       "another member setting the first password",
+			principal:  &member,
+			credential: uncredentialed,
+			err:        ErrForbidden,
+		},
+		{
+			name:       "unauthenticated",
+			credential: uncredentialed,
+			err:        ErrUnauthenticated,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, dm := newAuthenticationService(t, test.credential)
+			ctx := context.Background()
+			if test.principal != nil {
+				ctx = WithPrincipal(ctx, *test.principal)
+			}
+
+			err := a.ChangePassword(ctx, test.credential.AccountUUID, test.current, password)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+			saved := append(dm.inserted, dm.updated...)
+			if test.err != nil {
+				if len(saved) != 0 {
+					t.Fatalf("expected no credential to be saved, got %d", len(saved))
+				}
+				return
+			}
+			if len(saved) != 1 || (len(dm.inserted) == 1) != test.inserted {
+				t.Fatalf("expected inserted to be %t, got %d inserted and %d updated",
+					test.inserted, len(dm.inserted), len(dm.updated))
+			}
+			credential := saved[0].(infrastructure.Credential)
+			if credential.AccountUUID != test.credential.AccountUUID {
+				t.Fatalf("expected the credential of %s, got %s", test.credential.AccountUUID, credential.AccountUUID)
+			}
+			if verified, err := infrastructure.VerifyPassword(credential.PasswordHash, password); err != nil || !verified {
+				t.Fatalf("expected the password to be verified, got %t and %v", verified, err)
+			}
+			if credential.Role == "" || !credential.UpdatedAt.After(test.credential.UpdatedAt) {
+				t.Fatalf("expected a role and a newer modification time, got %+v", credential)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+
+	"github.com/freerware/tutor/domain"
+	"github.com/freerware/tutor/infrastructure"
+	u "github.com/gofrs/uuid"
+	"go.uber.org/zap"
+)
+
+// The decisions that authorization reaches.
+const (
+	decisionAllowed = "allowed"
+	decisionDenied  = "denied"
+)
+
+// authorizer decides whether the principal that the application acts on
+// behalf of may act upon an account, logging each decision for audit.
+type authorizer struct {
+	logger *zap.Logger
+}
+
+func newAuthorizer(logger *zap.Logger) authorizer {
+	return authorizer{logger: logger}
+}
+
+// authorize ensures that the principal may perform the provided action upon
+// the provided account, which only the account itself and admins may do.
+func (z authorizer) authorize(ctx context.Context, action string, accountUUID u.UUID) error {
+	principal, ok := PrincipalFrom(ctx)
+	switch {
+	case !ok:
+		z.audit(ctx, action, accountUUID, decisionDenied, "unauthenticated")
+		return ErrUnauthenticated
+	case principal.AccountUUID == accountUUID:
+		z.audit(ctx, action, accountUUID, decisionAllowed, "owner")
+	case principal.Role == RoleAdmin:
+		z.audit(ctx, action, accountUUID, decisionAllowed, "admin")
+	default:
+		z.audit(ctx, action, accountUUID, decisionDenied, "not owner")
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeRole ensures that the principal acts in the provided role, which
+// actions upon no particular account require.
+func (z authorizer) authorizeRole(ctx context.Context, action string, role string) error {
+	principal, ok := PrincipalFrom(ctx)
+	switch {
+	case !ok:
+		z.audit(ctx, action, u.Nil, decisionDenied, "unauthenticated")
+		return ErrUnauthenticated
+	case principal.Role == role:
+		z.audit(ctx, action, u.Nil, decisionAllowed, role)
+	default:
+		z.audit(ctx, action, u.Nil, decisionDenied, "not "+role)
+		return ErrForbidden
+	}
+	return nil
+}
+
+// visible provides the account as the principal may see it. Drafts are only
+// visible to their author, so they are withheld from everyone else.
+func (z authorizer) visible(ctx context.Context, action string, account domain.Account) domain.Account {
+	posts, drafts := []domain.Post{}, 0
+	for _, post := range account.Posts() {
+		if post.IsDraft() {
+			drafts++
+			continue
+		}
+		posts = append(posts, post)
+	}
+	if drafts == 0 {
+		return account
+	}
+	if principal, ok := PrincipalFrom(ctx); ok && principal.AccountUUID == account.UUID() {
+		z.audit(ctx, action, account.UUID(), decisionAllowed, "author", zap.Int("drafts", drafts))
+		return account
+	}
+	z.audit(ctx, action, account.UUID(), decisionDenied, "not author", zap.Int("drafts", drafts))
+	account.SetPosts(posts)
+	return account
+}
+
+// audit logs the decision reached when the principal attempted the provided
+// action upon the provided account, along with the reason for it.
+func (z authorizer) audit(
+	ctx context.Context,
+	action string,
+	accountUUID u.UUID,
+	decision string,
+	reason string,
+	fields ...zap.Field) {
+	principal, _ := PrincipalFrom(ctx)
+	infrastructure.LoggerFrom(ctx, z.logger).Named("audit").Info("authorization decision", append([]zap.Field{
+		zap.String("action", action),
+		zap.Stringer("account", accountUUID),
+		zap.Stringer("actor", principal.AccountUUID),
+		zap.String("role", principal.Role),
+		zap.String("decision", decision),
+		zap.String("reason", reason),
+	}, fields...)...)
+}
@@ -0,0 +1,19 @@
+package application
+
+import "errors"
+
+// Errors that are potentially thrown during application interactions.
+var (
+	ErrAccountNotFound      = errors.New("application: account not found")
+	ErrPostNotFound         = errors.New("application: post not found")
+	ErrAccountAlreadyExists = errors.New("application: account already exists")
+	ErrImportAborted        = errors.New("application: account was not imported since another account could not be")
+	ErrIdempotencyKeyInUse  = errors.New("application: idempotency key is in use by a concurrent request")
+	ErrInvalidCredentials   = errors.New("application: credentials are invalid")
+	ErrUnauthenticated      = errors.New("application: request is not authenticated")
+	ErrIncorrectPassword    = errors.New("application: current password is incorrect")
+	ErrInvalidPassword      = errors.New("application: passwords must be between 8 and 128 characters")
+	ErrUsernameTaken        = errors.New("application: username is in use by another account")
+	ErrForbidden            = errors.New("application: principal is not permitted to act upon the account")
+	ErrPostUUIDTaken        = errors.New("application: post UUID is in use by another account")
+)
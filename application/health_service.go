@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/freerware/tutor/infrastructure"
+	"go.uber.org/fx"
+)
+
+// healthCheckTimeout bounds how long each component is given to respond to
+// its health check.
+const healthCheckTimeout = 2 * time.Second
+
+// ComponentHealth describes the health of a component that the application
+// depends on, along with the reason it is unavailable, if it is.
+type ComponentHealth struct {
+	Component string
+	Err       error
+}
+
+// HealthService checks the health of the components that the application
+// depends on.
+type HealthService struct {
+	checks []infrastructure.HealthCheck
+}
+
+type HealthServiceParameters struct {
+	fx.In
+
+	Checks []infrastructure.HealthCheck `group:"healthChecks"`
+}
+
+func NewHealthService(parameters HealthServiceParameters) HealthService {
+	checks := append([]infrastructure.HealthCheck{}, parameters.Checks...)
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].Component < checks[j].Component
+	})
+	return HealthService{checks: checks}
+}
+
+// Check checks the health of every component concurrently, providing their
+// health in order of component name.
+func (h *HealthService) Check(ctx context.Context) []ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	health := make([]ComponentHealth, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func(i int, check infrastructure.HealthCheck) {
+			defer wg.Done()
+			health[i] = ComponentHealth{Component: check.Component, Err: check.Check(ctx)}
+		}(i, check)
+	}
+	wg.Wait()
+	return health
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/freerware/tutor/infrastructure"
+	"github.com/freerware/work/v4/unit"
+	"go.uber.org/fx"
+)
+
+type idempotencyRecordKey struct{}
+
+// WithIdempotencyRecord provides a context under which the changes made by
+// the application are saved along with the provided record, within the same
+// unit of work. Records without a response are not saved.
+func WithIdempotencyRecord(
+	ctx context.Context, record *infrastructure.IdempotencyRecord) context.Context {
+	return context.WithValue(ctx, idempotencyRecordKey{}, record)
+}
+
+// IdempotencyRecordFrom retrieves the record that changes made under the
+// provided context are saved along with, if any.
+func IdempotencyRecordFrom(ctx context.Context) (*infrastructure.IdempotencyRecord, bool) {
+	record, ok := ctx.Value(idempotencyRecordKey{}).(*infrastructure.IdempotencyRecord)
+	return record, ok && record != nil
+}
+
+// IdempotencyService retrieves the responses recorded for requests made
+// with idempotency keys.
+type IdempotencyService struct {
+	uniter  infrastructure.Uniter
+	queryer infrastructure.Queryer
+}
+
+type IdempotencyServiceParameters struct {
+	fx.In
+
+	Uniter  infrastructure.Uniter
+	Queryer infrastructure.Queryer
+}
+
+func NewIdempotencyService(
+	parameters IdempotencyServiceParameters) IdempotencyService {
+	return IdempotencyService{uniter: parameters.Uniter, queryer: parameters.Queryer}
+}
+
+// Get retrieves the unexpired record of the provided idempotency key, if
+// there is one.
+func (i *IdempotencyService) Get(key string) (*infrastructure.IdempotencyRecord, error) {
+	return i.queryer.QueryIdempotencyRecord(key, time.Now()).Execute()
+}
+
+// Save saves the idempotency record of the request on its own, for requests
+// whose response is only known once their changes are saved, such as bulk
+// imports that are saved in batches.
+func (i *IdempotencyService) Save(ctx context.Context) error {
+	if _, ok := IdempotencyRecordFrom(ctx); !ok {
+		return nil
+	}
+	unit, err := i.uniter.Unit(ctx)
+	if err != nil {
+		return err
+	}
+	return save(ctx, unit, i.queryer)
+}
+
+// save saves the provided unit of work, along with the idempotency record
+// of the request that made its changes, if any. Failing to save a record
+// whose key has since been recorded by another request means that the
+// requests were made concurrently.
+func save(ctx context.Context, u unit.Unit, queryer infrastructure.Queryer) error {
+	record, ok := IdempotencyRecordFrom(ctx)
+	if !ok || record.Status == 0 {
+		return saved(u.Save(ctx))
+	}
+	if err := u.Add(*record); err != nil {
+		return err
+	}
+	err := saved(u.Save(ctx))
+	if err == nil {
+		return nil
+	}
+	existing, findErr := queryer.QueryIdempotencyRecord(record.Key, time.Now()).Execute()
+	if findErr == nil && existing != nil {
+		return ErrIdempotencyKeyInUse
+	}
+	return err
+}
+
+// saved translates the errors of saving a unit of work into the errors of
+// the application.
+func saved(err error) error {
+	if errors.Is(err, infrastructure.ErrUsernameTaken) {
+		return ErrUsernameTaken
+	}
+	return err
+}
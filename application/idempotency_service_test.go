@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/freerware/tutor/infrastructure"
+)
+
+func TestIdempotencyServiceSave(t *testing.T) {
+	tests := []struct {
+		name   string
+		record *infrastructure.IdempotencyRecord
+		saved  bool
+	}{
+		{name: "without a record"},
+		{name: "without a response", record: &infrastructure.IdempotencyRecord{Key: "import"}},
+		{name: "with a response", record: &infrastructure.IdempotencyRecord{Key: "import", Status: 200}, saved: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dm := &dataMapper{}
+			i := NewIdempotencyService(IdempotencyServiceParameters{Uniter: newUniter(dm), Queryer: queryer{}})
+			ctx := context.Background()
+			if test.record != nil {
+				ctx = WithIdempotencyRecord(ctx, test.record)
+			}
+
+			if err := i.Save(ctx); err != nil {
+				t.Fatalf("failed to save: %v", err)
+			}
+			if saved := len(dm.inserted) == 1; saved != test.saved {
+				t.Fatalf("expected saved to be %t, got %d inserted", test.saved, len(dm.inserted))
+			}
+			if test.saved && dm.inserted[0].(infrastructure.IdempotencyRecord).Key != test.record.Key {
+				t.Fatalf("expected the record of %s, got %+v", test.record.Key, dm.inserted[0])
+			}
+		})
+	}
+}
@@ -4,4 +4,7 @@ import "go.uber.org/fx"
 
 var Module = fx.Options(
 	fx.Provide(NewAccountService),
+	fx.Provide(NewIdempotencyService),
+	fx.Provide(NewHealthService),
+	fx.Provide(NewAuthenticationService),
 )
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+
+	u "github.com/gofrs/uuid"
+)
+
+// The ways a principal can authenticate.
+const (
+	AuthenticatedWithPassword = "password"
+	AuthenticatedWithToken    = "token"
+)
+
+// The roles that a principal can act in. Members may only act upon their
+// own account, while admins may act upon any account.
+const (
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+// Principal identifies the account that a request is made on behalf of,
+// along with how the account authenticated and the role it acts in.
+type Principal struct {
+	AccountUUID     u.UUID
+	Username        string
+	AuthenticatedBy string
+	Role            string
+}
+
+type principalKey struct{}
+
+// WithPrincipal provides a context under which the application acts on
+// behalf of the provided principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom retrieves the principal that the application acts on behalf
+// of under the provided context, if the request was authenticated.
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
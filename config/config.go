@@ -31,12 +31,51 @@ func NewDefault() (Configuration, error) {
 }
 
 type Configuration struct {
-	Server   ServerConfiguration
-	Database DatabaseConfiguration
-	Metrics  MetricsConfiguration
+	Server         ServerConfiguration
+	GRPC           GRPCConfiguration `yaml:"grpc"`
+	Database       DatabaseConfiguration
+	Metrics        MetricsConfiguration
+	Negotiation    NegotiationConfiguration
+	Compression    CompressionConfiguration
+	Import         ImportConfiguration
+	Idempotency    IdempotencyConfiguration
+	Tracing        TracingConfiguration
+	Authentication AuthenticationConfiguration
 }
 
+// ServerConfiguration determines where the server listens, along with how
+// long, in seconds, it reports that it is not ready before it stops
+// accepting requests. The timeouts are in seconds as well, and like the
+// maximum size of the request headers in bytes, zero leaves them to the
+// defaults of net/http.
 type ServerConfiguration struct {
+	Host              string
+	Port              int
+	DrainPeriod       int              `yaml:"drainPeriod"`
+	ReadTimeout       int              `yaml:"readTimeout"`
+	ReadHeaderTimeout int              `yaml:"readHeaderTimeout"`
+	WriteTimeout      int              `yaml:"writeTimeout"`
+	IdleTimeout       int              `yaml:"idleTimeout"`
+	MaxHeaderBytes    int              `yaml:"maxHeaderBytes"`
+	TLS               TLSConfiguration `yaml:"tls"`
+}
+
+// TLSConfiguration determines how the server secures its connections. The
+// server only serves TLS when a certificate is provided, and only verifies
+// client certificates when a client CA bundle is provided. The minimum
+// version is either 1.2 or 1.3, and the cipher policy is one of default,
+// intermediate or modern. Client authentication is either required or
+// optional.
+type TLSConfiguration struct {
+	CertFile     string `yaml:"certFile"`
+	KeyFile      string `yaml:"keyFile"`
+	MinVersion   string `yaml:"minVersion"`
+	CipherPolicy string `yaml:"cipherPolicy"`
+	ClientCAFile string `yaml:"clientCAFile"`
+	ClientAuth   string `yaml:"clientAuth"`
+}
+
+type GRPCConfiguration struct {
 	Host string
 	Port int
 }
@@ -56,10 +95,71 @@ func (c DatabaseConfiguration) DSN() string {
 		c.User, c.Password, c.Host, c.Port, c.Name, c.ParseTime, c.Charset)
 }
 
+// MetricsConfiguration determines where metrics are reported: statsd
+// reports them to the statsd server at the host and port, prometheus serves
+// them at /metrics on the host and port of the Prometheus configuration, and
+// none discards them. An empty reporter is statsd.
 type MetricsConfiguration struct {
+	Reporter         string
 	Host             string
 	Port             int
 	Prefix           string
 	MaxFlushInterval int `yaml:"maxFlushInterval"`
 	MaxFlushBytes    int `yaml:"maxFlushBytes"`
+	Prometheus       PrometheusConfiguration
+}
+
+// PrometheusConfiguration determines where metrics are served to be scraped
+// by Prometheus.
+type PrometheusConfiguration struct {
+	Host string
+	Port int
+}
+
+// NegotiationConfiguration determines how each route negotiates the
+// representations it serves. Routes are identified by their path template,
+// such as /accounts/{uuid}, and use the default strategy unless listed.
+type NegotiationConfiguration struct {
+	Strategy string
+	Routes   map[string]string
+}
+
+// CompressionConfiguration determines when representations are compressed,
+// and bounds the size of the request bodies that are decompressed.
+type CompressionConfiguration struct {
+	MinimumSize        int `yaml:"minimumSize"`
+	MaximumRequestSize int `yaml:"maximumRequestSize"`
+}
+
+// ImportConfiguration determines how many accounts are saved within each
+// unit of work while importing accounts in bulk.
+type ImportConfiguration struct {
+	BatchSize int `yaml:"batchSize"`
+}
+
+// IdempotencyConfiguration determines how long, in seconds, the responses to
+// requests made with an idempotency key are replayed to their retries.
+type IdempotencyConfiguration struct {
+	TTL int `yaml:"ttl"`
+}
+
+// TracingConfiguration determines where spans are exported: otlp exports
+// them to the collector at the endpoint over HTTP, stdout writes them to the
+// file, or to standard output when no file is provided, and none discards
+// them. Traces without a sampled parent are sampled at the ratio, between 0
+// and 1.
+type TracingConfiguration struct {
+	Exporter    string
+	Endpoint    string
+	Insecure    bool
+	File        string
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
+// AuthenticationConfiguration determines the key that bearer tokens are
+// signed with, which must be at least 32 bytes, along with how long, in
+// seconds, the tokens remain valid.
+type AuthenticationConfiguration struct {
+	TokenKey string `yaml:"tokenKey"`
+	TokenTTL int    `yaml:"tokenTTL"`
 }
@@ -129,6 +129,36 @@ func (a *Account) HasPost(post Post) bool {
 	return false
 }
 
+func (a Account) Post(uuid u.UUID) (Post, bool) {
+	for _, p := range a.posts {
+		if p.UUID() == uuid {
+			return p, true
+		}
+	}
+	return Post{}, false
+}
+
+func (a *Account) ReplacePost(post Post) error {
+	for i, p := range a.posts {
+		if p.UUID() == post.UUID() {
+			post.SetAuthorUUID(a.UUID())
+			a.posts[i] = post
+			return nil
+		}
+	}
+	return ErrPostNotFound
+}
+
+func (a *Account) RemovePost(uuid u.UUID) error {
+	for i, p := range a.posts {
+		if p.UUID() == uuid {
+			a.posts = append(a.posts[:i:i], a.posts[i+1:]...)
+			return nil
+		}
+	}
+	return ErrPostNotFound
+}
+
 func (a Account) CreatedAt() time.Time {
 	return a.createdAt
 }
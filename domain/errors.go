@@ -11,4 +11,5 @@ var (
 	ErrInvalidDeletedAt     = errors.New("domain: deletion time cannot be prior to account creation or modification time")
 	ErrNegativeLikes        = errors.New("domain: likes cannot be negative")
 	ErrPostAlreadyPublished = errors.New("domain: post is already published")
+	ErrPostNotFound         = errors.New("domain: post does not belong to the account")
 )
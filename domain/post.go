@@ -37,7 +37,9 @@ func NewPost(parameters PostParameters) (Post, error) {
 	post.SetTitle(parameters.Title)
 	post.SetContent(parameters.Content)
 	post.SetDraft(parameters.Draft)
-	post.SetLikes(parameters.Likes)
+	if err := post.SetLikes(parameters.Likes); err != nil {
+		return Post{}, err
+	}
 	if err := post.SetCreatedAt(parameters.CreatedAt); err != nil {
 		return Post{}, err
 	}
@@ -0,0 +1,230 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+)
+
+// AccountFilter narrows the accounts matched by a query. Unset criteria
+// match every account.
+type AccountFilter struct {
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	Deleted       *bool
+}
+
+// where provides the conditions and arguments of the SQL WHERE clause that
+// applies the filter to the ACCOUNT table aliased as A.
+func (f AccountFilter) where() (string, []any) {
+	conditions := []string{}
+	args := []any{}
+	if f.CreatedAfter != nil {
+		conditions = append(conditions, "A.CREATED_AT >= ?")
+		args = append(args, *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		conditions = append(conditions, "A.CREATED_AT < ?")
+		args = append(args, *f.CreatedBefore)
+	}
+	if f.UpdatedAfter != nil {
+		conditions = append(conditions, "A.UPDATED_AT >= ?")
+		args = append(args, *f.UpdatedAfter)
+	}
+	if f.Deleted != nil && *f.Deleted {
+		conditions = append(conditions, "A.DELETED_AT IS NOT NULL")
+	}
+	if f.Deleted != nil && !*f.Deleted {
+		conditions = append(conditions, "A.DELETED_AT IS NULL")
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// AccountCursorQuery is a query whose matches are retrieved one at a time.
+type AccountCursorQuery interface {
+	Execute(context.Context) (AccountCursor, error)
+}
+
+// AccountCursor iterates over the accounts matched by a query, reading them
+// row by row from a consistent snapshot of the database.
+type AccountCursor interface {
+	Next() bool
+	Account() domain.Account
+	Err() error
+	Close() error
+}
+
+type findAccountsCursor struct {
+	accountQuery
+
+	filter AccountFilter
+	posts  bool
+}
+
+func NewFindAccountsCursorQuery(db *sql.DB, filter AccountFilter, posts bool) AccountCursorQuery {
+	return &findAccountsCursor{
+		accountQuery: accountQuery{
+			db: db,
+		},
+		filter: filter,
+		posts:  posts,
+	}
+}
+
+func (q *findAccountsCursor) Execute(ctx context.Context) (AccountCursor, error) {
+
+	// read within a read only transaction, so that every row comes from
+	// the same snapshot.
+	tx, err := q.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// posts are joined rather than queried separately, since the connection
+	// is occupied by the rows until they have all been read.
+	columns := "A.CREATED_AT, A.DELETED_AT, A.GIVEN_NAME, A.PRIMARY_CREDENTIAL, A.SURNAME, A.UPDATED_AT, A.UUID"
+	from := "ACCOUNT A"
+	order := "A.CREATED_AT, A.UUID"
+	if q.posts {
+		columns += ", P.CREATED_AT, P.DELETED_AT, P.DRAFT, P.LIKE_COUNT, P.UPDATED_AT, P.UUID, P.TITLE, P.CONTENT"
+		from += " LEFT JOIN POST P ON P.AUTHOR_UUID = A.UUID"
+		order += ", P.CREATED_AT, P.UUID"
+	}
+	where, args := q.filter.where()
+	rows, err := tx.QueryContext(ctx, "SELECT "+columns+" FROM "+from+where+" ORDER BY "+order, args...)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return &accountCursor{tx: tx, rows: rows, posts: q.posts}, nil
+}
+
+// accountCursor assembles accounts from consecutive rows, since an account
+// spans one row for each of its posts when they are joined.
+type accountCursor struct {
+	tx    *sql.Tx
+	rows  *sql.Rows
+	posts bool
+
+	current domain.AccountParameters
+	next    *domain.AccountParameters
+	err     error
+}
+
+func (c *accountCursor) Next() bool {
+	if c.err != nil {
+		return false
+	}
+
+	// resume with the account read ahead by the previous call, if any.
+	var params *domain.AccountParameters
+	if c.next != nil {
+		params, c.next = c.next, nil
+	}
+	for c.rows.Next() {
+		p, err := c.scan()
+		if err != nil {
+			c.err = err
+			return false
+		}
+		if params == nil {
+			params = &p
+			continue
+		}
+		if p.UUID != params.UUID {
+			c.next = &p
+			break
+		}
+		params.Posts = append(params.Posts, p.Posts...)
+	}
+	if c.next == nil {
+		if c.err = c.rows.Err(); c.err != nil {
+			return false
+		}
+	}
+	if params == nil {
+		return false
+	}
+	c.current = *params
+	return true
+}
+
+// scan reads the current row, which carries at most one post.
+func (c *accountCursor) scan() (domain.AccountParameters, error) {
+	var params domain.AccountParameters
+	dest := []any{
+		&params.CreatedAt,
+		&params.DeletedAt,
+		&params.GivenName,
+		&params.Username,
+		&params.Surname,
+		&params.UpdatedAt,
+		&params.UUID,
+	}
+	var (
+		createdAt, updatedAt sql.NullTime
+		deletedAt            *time.Time
+		draft                sql.NullBool
+		likes                sql.NullInt64
+		uuid                 u.NullUUID
+		title, content       sql.NullString
+	)
+	if c.posts {
+		dest = append(dest,
+			&createdAt,
+			&deletedAt,
+			&draft,
+			&likes,
+			&updatedAt,
+			&uuid,
+			&title,
+			&content,
+		)
+	}
+	if err := c.rows.Scan(dest...); err != nil {
+		return params, err
+	}
+
+	// accounts without posts are joined with a row of NULLs.
+	if uuid.Valid {
+		params.Posts = []domain.Post{domain.ReconstitutePost(domain.PostParameters{
+			UUID:       uuid.UUID,
+			AuthorUUID: params.UUID,
+			Title:      title.String,
+			Content:    content.String,
+			Draft:      draft.Bool,
+			Likes:      int(likes.Int64),
+			CreatedAt:  createdAt.Time,
+			UpdatedAt:  updatedAt.Time,
+			DeletedAt:  deletedAt,
+		})}
+	}
+	return params, nil
+}
+
+func (c *accountCursor) Account() domain.Account {
+	return domain.ReconstituteAccount(c.current)
+}
+
+func (c *accountCursor) Err() error {
+	return c.err
+}
+
+func (c *accountCursor) Close() error {
+	if err := c.rows.Close(); err != nil {
+		c.tx.Rollback()
+		return err
+	}
+	return c.tx.Commit()
+}
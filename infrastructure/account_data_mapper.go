@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/freerware/morph"
 	"github.com/freerware/tutor/domain"
 	"github.com/freerware/work/v4/unit"
+	"github.com/go-sql-driver/mysql"
 	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -20,18 +23,32 @@ var (
 	// ErrInvalidType represents an error that indicates a unexpected type
 	// was provided to the data mapper.
 	ErrInvalidType = errors.New("infrastructure: invalid type provided to data mapper")
+
+	// ErrUsernameTaken represents an error that indicates another account
+	// already has the username of the account being saved.
+	ErrUsernameTaken = errors.New("infrastructure: username is in use by another account")
 )
 
+// usernameIndex is the unique index that keeps usernames from identifying
+// more than one account.
+const usernameIndex = "ACCOUNT_PRIMARY_CREDENTIAL"
+
+// mysqlDuplicateEntry is the number of the MySQL error raised when a
+// statement violates a unique index.
+const mysqlDuplicateEntry = 1062
+
 type AccountDataMapperParameters struct {
 	fx.In
 
-	DB     *sql.DB `name:"rwDB"`
-	Logger *zap.Logger
+	DB             *sql.DB `name:"rwDB"`
+	Logger         *zap.Logger
+	TracerProvider trace.TracerProvider
 }
 
 type AccountDataMapper struct {
 	db           *sql.DB
 	logger       *zap.Logger
+	tracer       trace.Tracer
 	accountTable morph.Table
 	postsTable   morph.Table
 }
@@ -43,7 +60,8 @@ func NewAccountDataMapper(parameters AccountDataMapperParameters) AccountDataMap
 		morph.WithInferredColumnNames(morph.ScreamingSnakeCaseStrategy),
 		morph.WithInferredTableAlias(morph.UpperCaseStrategy, 1),
 		morph.WithColumnNameMapping("Username", "PRIMARY_CREDENTIAL"),
-		morph.WithoutMethods("HasPost", "Posts", "AddPost", "AddPosts"),
+		morph.WithoutMethods(
+			"HasPost", "Posts", "AddPost", "AddPosts", "Post", "ReplacePost", "RemovePost"),
 	}
 	at := morph.Must(morph.Reflect(domain.Account{}, opts...))
 
@@ -61,22 +79,26 @@ func NewAccountDataMapper(parameters AccountDataMapperParameters) AccountDataMap
 	return AccountDataMapper{
 		db:           parameters.DB,
 		logger:       parameters.Logger,
+		tracer:       parameters.TracerProvider.Tracer(instrumentationName),
 		accountTable: at,
 		postsTable:   pt,
 	}
 }
 
-func (dm *AccountDataMapper) FindPosts(ctx context.Context, mCtx unit.MapperContext, accountUUID uuid.UUID) ([]domain.Post, error) {
+func (dm *AccountDataMapper) FindPosts(ctx context.Context, mCtx unit.MapperContext, accountUUID uuid.UUID) (_ []domain.Post, err error) {
 	query := "SELECT " + strings.Join(dm.postsTable.ColumnNames(), ", ") + " FROM " + dm.postsTable.Name() + " WHERE " + morph.Must(dm.postsTable.ColumnName("AuthorUUID")) + " = ?;"
-	stmt, err := mCtx.Tx.Prepare(query)
+	ctx, span := startStatement(ctx, dm.tracer, query)
+	defer func() { EndSpan(span, err) }()
+
+	stmt, err := mCtx.Tx.PrepareContext(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, failed(ctx, dm.logger, query, err)
 	}
 	defer stmt.Close()
 
 	rows, err := stmt.QueryContext(ctx, accountUUID)
 	if err != nil {
-		return nil, err
+		return nil, failed(ctx, dm.logger, query, err)
 	}
 	defer rows.Close()
 
@@ -104,23 +126,42 @@ func (dm *AccountDataMapper) FindPosts(ctx context.Context, mCtx unit.MapperCont
 }
 
 func (dm *AccountDataMapper) Find(ctx context.Context, mCtx unit.MapperContext, uuid uuid.UUID) (domain.Account, error) {
-	sql, err := dm.accountTable.SelectQuery()
+	params, err := dm.findAccount(ctx, mCtx, uuid)
 	if err != nil {
 		return domain.Account{}, err
 	}
 
-	stmt, err := mCtx.Tx.Prepare(sql)
+	posts, err := dm.FindPosts(ctx, mCtx, params.UUID)
 	if err != nil {
 		return domain.Account{}, err
 	}
+	params.Posts = posts
+	return domain.ReconstituteAccount(params), nil
+}
+
+// findAccount retrieves the state of the account with the provided UUID,
+// without its posts.
+func (dm *AccountDataMapper) findAccount(
+	ctx context.Context, mCtx unit.MapperContext, uuid uuid.UUID) (params domain.AccountParameters, err error) {
+	sql, err := dm.accountTable.SelectQuery()
+	if err != nil {
+		return params, err
+	}
+	ctx, span := startStatement(ctx, dm.tracer, sql)
+	defer func() { EndSpan(span, err) }()
+
+	stmt, err := mCtx.Tx.PrepareContext(ctx, sql)
+	if err != nil {
+		return params, failed(ctx, dm.logger, sql, err)
+	}
+	defer stmt.Close()
 
 	rows, err := stmt.QueryContext(ctx, uuid)
 	if err != nil {
-		stmt.Close()
-		return domain.Account{}, err
+		return params, failed(ctx, dm.logger, sql, err)
 	}
+	defer rows.Close()
 
-	var params domain.AccountParameters
 	if rows.Next() {
 		err = rows.Scan(
 			&params.CreatedAt,
@@ -131,20 +172,8 @@ func (dm *AccountDataMapper) Find(ctx context.Context, mCtx unit.MapperContext,
 			&params.UpdatedAt,
 			&params.UUID,
 		)
-		if err != nil {
-			rows.Close()
-			return domain.Account{}, err
-		}
 	}
-	rows.Close()
-	stmt.Close()
-
-	posts, err := dm.FindPosts(ctx, mCtx, params.UUID)
-	if err != nil {
-		return domain.Account{}, err
-	}
-	params.Posts = posts
-	return domain.ReconstituteAccount(params), nil
+	return params, err
 }
 
 func (dm *AccountDataMapper) Insert(ctx context.Context, mCtx unit.MapperContext, accounts ...any) error {
@@ -154,15 +183,8 @@ func (dm *AccountDataMapper) Insert(ctx context.Context, mCtx unit.MapperContext
 			return err
 		}
 
-		stmt, err := mCtx.Tx.Prepare(sql)
-		if err != nil {
-			return err
-		}
-		defer stmt.Close()
-
-		_, err = stmt.ExecContext(ctx, args...)
-		if err != nil {
-			return err
+		if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
+			return usernameTaken(err)
 		}
 
 		acc := account.(domain.Account)
@@ -172,14 +194,7 @@ func (dm *AccountDataMapper) Insert(ctx context.Context, mCtx unit.MapperContext
 				return err
 			}
 
-			stmt, err := mCtx.Tx.Prepare(sql)
-			if err != nil {
-				return err
-			}
-			defer stmt.Close()
-
-			_, err = stmt.ExecContext(ctx, args...)
-			if err != nil {
+			if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
 				return err
 			}
 		}
@@ -195,15 +210,8 @@ func (dm *AccountDataMapper) Update(ctx context.Context, mCtx unit.MapperContext
 			return err
 		}
 
-		stmt, err := mCtx.Tx.Prepare(sql)
-		if err != nil {
-			return err
-		}
-		defer stmt.Close()
-
-		_, err = stmt.ExecContext(ctx, args...)
-		if err != nil {
-			return err
+		if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
+			return usernameTaken(err)
 		}
 
 		acc := account.(domain.Account)
@@ -220,14 +228,7 @@ func (dm *AccountDataMapper) Update(ctx context.Context, mCtx unit.MapperContext
 					return err
 				}
 
-				stmt, err := mCtx.Tx.Prepare(sql)
-				if err != nil {
-					return err
-				}
-				defer stmt.Close()
-
-				_, err = stmt.ExecContext(ctx, args...)
-				if err != nil {
+				if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
 					return err
 				}
 				continue
@@ -240,14 +241,7 @@ func (dm *AccountDataMapper) Update(ctx context.Context, mCtx unit.MapperContext
 					return err
 				}
 
-				stmt, err := mCtx.Tx.Prepare(sql)
-				if err != nil {
-					return err
-				}
-				defer stmt.Close()
-
-				_, err = stmt.ExecContext(ctx, args...)
-				if err != nil {
+				if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
 					return err
 				}
 				continue
@@ -262,14 +256,7 @@ func (dm *AccountDataMapper) Update(ctx context.Context, mCtx unit.MapperContext
 					return err
 				}
 
-				stmt, err := mCtx.Tx.Prepare(sql)
-				if err != nil {
-					return err
-				}
-				defer stmt.Close()
-
-				_, err = stmt.ExecContext(ctx, args...)
-				if err != nil {
+				if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
 					return err
 				}
 			}
@@ -286,14 +273,7 @@ func (dm *AccountDataMapper) Delete(ctx context.Context, mCtx unit.MapperContext
 			return err
 		}
 
-		stmt, err := mCtx.Tx.Prepare(sql)
-		if err != nil {
-			return err
-		}
-		defer stmt.Close()
-
-		_, err = stmt.ExecContext(ctx, args...)
-		if err != nil {
+		if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
 			return err
 		}
 
@@ -304,14 +284,7 @@ func (dm *AccountDataMapper) Delete(ctx context.Context, mCtx unit.MapperContext
 				return err
 			}
 
-			stmt, err := mCtx.Tx.Prepare(sql)
-			if err != nil {
-				return err
-			}
-			defer stmt.Close()
-
-			_, err = stmt.ExecContext(ctx, args...)
-			if err != nil {
+			if err = dm.exec(ctx, mCtx, sql, args...); err != nil {
 				return err
 			}
 		}
@@ -319,3 +292,34 @@ func (dm *AccountDataMapper) Delete(ctx context.Context, mCtx unit.MapperContext
 
 	return nil
 }
+
+// exec executes the provided statement within the transaction of the unit,
+// describing it with a span.
+func (dm *AccountDataMapper) exec(
+	ctx context.Context, mCtx unit.MapperContext, statement string, args ...any) (err error) {
+	ctx, span := startStatement(ctx, dm.tracer, statement)
+	defer func() { EndSpan(span, err) }()
+
+	stmt, err := mCtx.Tx.PrepareContext(ctx, statement)
+	if err != nil {
+		return failed(ctx, dm.logger, statement, err)
+	}
+	defer stmt.Close()
+
+	if _, err = stmt.ExecContext(ctx, args...); err != nil {
+		return failed(ctx, dm.logger, statement, err)
+	}
+	return nil
+}
+
+// usernameTaken identifies the violations of the unique username index,
+// which happen when another account claimed the username concurrently.
+func usernameTaken(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) &&
+		mysqlErr.Number == mysqlDuplicateEntry &&
+		strings.Contains(mysqlErr.Message, usernameIndex) {
+		return fmt.Errorf("%w: %w", ErrUsernameTaken, err)
+	}
+	return err
+}
@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestUsernameTaken(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		taken bool
+	}{
+		{
+			name: "username index",
+			err: &mysql.MySQLError{
+				Number:  mysqlDuplicateEntry,
+				Message: "Duplicate entry 'freer' for key 'ACCOUNT.ACCOUNT_PRIMARY_CREDENTIAL'",
+			},
+			taken: true,
+		},
+		{
+			name: "primary key",
+			err: &mysql.MySQLError{
+				Number:  mysqlDuplicateEntry,
+				Message: "Duplicate entry 'a6f1f1ae' for key 'ACCOUNT.PRIMARY'",
+			},
+		},
+		{
+			name: "other error",
+			err:  errors.New("connection refused"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := usernameTaken(test.err)
+			if taken := errors.Is(err, ErrUsernameTaken); taken != test.taken {
+				t.Fatalf("expected taken to be %t, got %t", test.taken, taken)
+			}
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v to wrap %v", err, test.err)
+			}
+		})
+	}
+}
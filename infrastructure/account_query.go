@@ -2,13 +2,70 @@ package infrastructure
 
 import (
 	"database/sql"
+	"strings"
+
 	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
 )
 
 type AccountQuery interface {
 	Execute() ([]domain.Account, error)
 }
 
+// AccountPageQuery is a query that retrieves a page of accounts along with
+// the total number of accounts, both from the same snapshot.
+type AccountPageQuery interface {
+	Execute() ([]domain.Account, int, error)
+}
+
 type accountQuery struct {
 	db *sql.DB
 }
+
+// posts retrieves the posts written by the provided authors within the
+// provided transaction, keyed by the author UUID.
+func (q accountQuery) posts(tx *sql.Tx, authors ...u.UUID) (map[u.UUID][]domain.Post, error) {
+	posts := make(map[u.UUID][]domain.Post)
+	if len(authors) == 0 {
+		return posts, nil
+	}
+
+	placeholders := make([]string, len(authors))
+	args := make([]any, len(authors))
+	for i, author := range authors {
+		placeholders[i] = "?"
+		args[i] = author.String()
+	}
+	statement, err := tx.Prepare("SELECT AUTHOR_UUID, CREATED_AT, DELETED_AT, DRAFT, LIKE_COUNT, UPDATED_AT, UUID, TITLE, CONTENT FROM POST WHERE AUTHOR_UUID IN (" + strings.Join(placeholders, ", ") + ") ORDER BY CREATED_AT, UUID;")
+	if err != nil {
+		return posts, err
+	}
+	defer statement.Close()
+
+	rows, err := statement.Query(args...)
+	if err != nil {
+		return posts, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var params domain.PostParameters
+		err = rows.Scan(
+			&params.AuthorUUID,
+			&params.CreatedAt,
+			&params.DeletedAt,
+			&params.Draft,
+			&params.Likes,
+			&params.UpdatedAt,
+			&params.UUID,
+			&params.Title,
+			&params.Content,
+		)
+		if err != nil {
+			return posts, err
+		}
+		posts[params.AuthorUUID] =
+			append(posts[params.AuthorUUID], domain.ReconstitutePost(params))
+	}
+	return posts, rows.Err()
+}
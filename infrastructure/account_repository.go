@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"context"
 	"errors"
 
 	"github.com/freerware/tutor/domain"
@@ -8,6 +9,10 @@ import (
 	u "github.com/gofrs/uuid"
 )
 
+// ErrAccountAlreadyExists indicates that an account being added is already
+// within the repository.
+var ErrAccountAlreadyExists = errors.New("infrastructure: account already exists")
+
 // AccountRepository represents a collection of all
 // accounts within the application.
 type AccountRepository interface {
@@ -16,24 +21,51 @@ type AccountRepository interface {
 	Remove(domain.Account) error
 	Add(domain.Account) error
 	Find(AccountQuery) ([]domain.Account, error)
-	Size() (int, error)
+	FindPage(AccountPageQuery) ([]domain.Account, int, error)
+	Each(context.Context, AccountCursorQuery, func(domain.Account) error) error
 }
 
 type accountRepository struct {
+	ctx     context.Context
 	unit    unit.Unit
 	queryer Queryer
 }
 
-func NewAccountRepository(unit unit.Unit, queryer Queryer) AccountRepository {
-	return &accountRepository{unit: unit, queryer: queryer}
+// NewAccountRepository constructs a repository for the request that the
+// provided context belongs to.
+func NewAccountRepository(ctx context.Context, unit unit.Unit, queryer Queryer) AccountRepository {
+	return &accountRepository{ctx: ctx, unit: unit, queryer: queryer}
 }
 
 func (r *accountRepository) Find(query AccountQuery) ([]domain.Account, error) {
 	return query.Execute()
 }
 
+func (r *accountRepository) FindPage(query AccountPageQuery) ([]domain.Account, int, error) {
+	return query.Execute()
+}
+
+func (r *accountRepository) Each(
+	ctx context.Context, query AccountCursorQuery, each func(domain.Account) error) (err error) {
+	cursor, err := query.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := cursor.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	for cursor.Next() {
+		if err = each(cursor.Account()); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
 func (r *accountRepository) Get(uuid u.UUID) (*domain.Account, error) {
-	query := r.queryer.Query(uuid)
+	query := r.queryer.Query(r.ctx, uuid)
 	matches, err := r.Find(query)
 	if err != nil {
 		return nil, err
@@ -90,15 +122,10 @@ func (r *accountRepository) Add(account domain.Account) error {
 
 	// if the account is within the repository, throw an error.
 	if c != nil {
-		return errors.New("account already exists")
+		return ErrAccountAlreadyExists
 	}
 
 	// otherwise, remove the account.
 	r.unit.Add(account)
 	return nil
 }
-
-func (r *accountRepository) Size() (int, error) {
-	//TODO(FREER) use queryer to get query for select all.
-	return 0, nil
-}
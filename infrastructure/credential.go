@@ -0,0 +1,96 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	u "github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Credential records the hash of the password that an account authenticates
+// with, along with the role it acts in. The username of the account is
+// retrieved along with it, but is persisted as part of the account. Accounts
+// that cannot authenticate with a password are retrieved with an empty hash.
+type Credential struct {
+	AccountUUID  u.UUID
+	Username     string
+	PasswordHash string
+	Role         string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// HasPassword indicates if the account can authenticate with a password.
+func (c Credential) HasPassword() bool {
+	return c.PasswordHash != ""
+}
+
+// CredentialQuery is a query that retrieves the credentials of accounts
+// that have not been deleted.
+type CredentialQuery interface {
+	Execute() ([]Credential, error)
+}
+
+type findCredentials struct {
+	db     *sql.DB
+	ctx    context.Context
+	tracer trace.Tracer
+	column string
+	value  string
+}
+
+// NewFindCredentialsByUsernameQuery constructs a query that retrieves the
+// credentials of the accounts with the provided username.
+func NewFindCredentialsByUsernameQuery(
+	ctx context.Context, db *sql.DB, tracer trace.Tracer, username string) CredentialQuery {
+	return &findCredentials{db: db, ctx: ctx, tracer: tracer, column: "A.PRIMARY_CREDENTIAL", value: username}
+}
+
+// NewFindCredentialQuery constructs a query that retrieves the credential of
+// the account with the provided UUID.
+func NewFindCredentialQuery(
+	ctx context.Context, db *sql.DB, tracer trace.Tracer, accountUUID u.UUID) CredentialQuery {
+	return &findCredentials{db: db, ctx: ctx, tracer: tracer, column: "A.UUID", value: accountUUID.String()}
+}
+
+func (q *findCredentials) Execute() (_ []Credential, err error) {
+	credentials := []Credential{}
+	query := "SELECT A.UUID, A.PRIMARY_CREDENTIAL, C.PASSWORD_HASH, C.ROLE, C.CREATED_AT, C.UPDATED_AT FROM ACCOUNT A LEFT JOIN CREDENTIAL C ON C.ACCOUNT_UUID = A.UUID WHERE " + q.column + " = ? AND A.DELETED_AT IS NULL"
+	ctx, span := startStatement(q.ctx, q.tracer, query)
+	defer func() { EndSpan(span, err) }()
+
+	rows, err := q.db.QueryContext(ctx, query, q.value)
+	if err != nil {
+		return credentials, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			credential Credential
+			hash       sql.NullString
+			role       sql.NullString
+			createdAt  sql.NullTime
+			updatedAt  sql.NullTime
+		)
+		err = rows.Scan(
+			&credential.AccountUUID,
+			&credential.Username,
+			&hash,
+			&role,
+			&createdAt,
+			&updatedAt,
+		)
+		if err != nil {
+			return credentials, err
+		}
+		credential.PasswordHash = hash.String
+		credential.Role = role.String
+		credential.CreatedAt = createdAt.Time
+		credential.UpdatedAt = updatedAt.Time
+		credentials = append(credentials, credential)
+	}
+	return credentials, rows.Err()
+}
@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/freerware/work/v4/unit"
+	"go.uber.org/zap"
+)
+
+// CredentialDataMapper persists credentials within the same transaction as
+// the accounts they belong to.
+type CredentialDataMapper struct {
+	logger *zap.Logger
+}
+
+func NewCredentialDataMapper(logger *zap.Logger) CredentialDataMapper {
+	return CredentialDataMapper{logger: logger}
+}
+
+func (dm *CredentialDataMapper) Insert(ctx context.Context, mCtx unit.MapperContext, credentials ...any) error {
+	for _, c := range credentials {
+		credential, ok := c.(Credential)
+		if !ok {
+			return ErrInvalidType
+		}
+		insert := "INSERT INTO CREDENTIAL (ACCOUNT_UUID, PASSWORD_HASH, ROLE, CREATED_AT, UPDATED_AT) VALUES (?, ?, ?, ?, ?)"
+		_, err := mCtx.Tx.ExecContext(ctx,
+			insert,
+			credential.AccountUUID.String(),
+			credential.PasswordHash,
+			credential.Role,
+			credential.CreatedAt,
+			credential.UpdatedAt,
+		)
+		if err != nil {
+			return failed(ctx, dm.logger, insert, err)
+		}
+	}
+	return nil
+}
+
+func (dm *CredentialDataMapper) Update(ctx context.Context, mCtx unit.MapperContext, credentials ...any) error {
+	for _, c := range credentials {
+		credential, ok := c.(Credential)
+		if !ok {
+			return ErrInvalidType
+		}
+		update := "UPDATE CREDENTIAL SET PASSWORD_HASH = ?, UPDATED_AT = ? WHERE ACCOUNT_UUID = ?"
+		_, err := mCtx.Tx.ExecContext(ctx,
+			update,
+			credential.PasswordHash,
+			credential.UpdatedAt,
+			credential.AccountUUID.String(),
+		)
+		if err != nil {
+			return failed(ctx, dm.logger, update, err)
+		}
+	}
+	return nil
+}
+
+func (dm *CredentialDataMapper) Delete(ctx context.Context, mCtx unit.MapperContext, credentials ...any) error {
+	for _, c := range credentials {
+		credential, ok := c.(Credential)
+		if !ok {
+			return ErrInvalidType
+		}
+		remove := "DELETE FROM CREDENTIAL WHERE ACCOUNT_UUID = ?"
+		if _, err := mCtx.Tx.ExecContext(ctx, remove, credential.AccountUUID.String()); err != nil {
+			return failed(ctx, dm.logger, remove, err)
+		}
+	}
+	return nil
+}
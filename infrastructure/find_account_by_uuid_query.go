@@ -1,24 +1,31 @@
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/freerware/tutor/domain"
 	u "github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type findAccountByUUID struct {
 	accountQuery
 
-	uuid u.UUID
+	ctx    context.Context
+	tracer trace.Tracer
+	uuid   u.UUID
 }
 
-func NewFindAccountByUUIDQuery(db *sql.DB, uuid u.UUID) AccountQuery {
+func NewFindAccountByUUIDQuery(
+	ctx context.Context, db *sql.DB, tracer trace.Tracer, uuid u.UUID) AccountQuery {
 	return &findAccountByUUID{
 		accountQuery: accountQuery{
 			db: db,
 		},
-		uuid: uuid,
+		ctx:    ctx,
+		tracer: tracer,
+		uuid:   uuid,
 	}
 }
 
@@ -28,15 +35,19 @@ func (q *findAccountByUUID) Execute() ([]domain.Account, error) {
 	return q.accounts()
 }
 
-func (q findAccountByUUID) accounts() ([]domain.Account, error) {
+func (q findAccountByUUID) accounts() (_ []domain.Account, err error) {
 	matches := []domain.Account{}
-	statement, err := q.db.Prepare("SELECT CREATED_AT, DELETED_AT, GIVEN_NAME, PRIMARY_CREDENTIAL, SURNAME, UPDATED_AT, UUID FROM ACCOUNT WHERE UUID = ?")
+	query := "SELECT CREATED_AT, DELETED_AT, GIVEN_NAME, PRIMARY_CREDENTIAL, SURNAME, UPDATED_AT, UUID FROM ACCOUNT WHERE UUID = ?"
+	ctx, span := startStatement(q.ctx, q.tracer, query)
+	defer func() { EndSpan(span, err) }()
+
+	statement, err := q.db.PrepareContext(ctx, query)
 	if err != nil {
 		return matches, err
 	}
 	defer statement.Close()
 
-	rows, err := statement.Query(q.uuid.String())
+	rows, err := statement.QueryContext(ctx, q.uuid.String())
 	if err != nil {
 		return matches, err
 	}
@@ -57,39 +68,49 @@ func (q findAccountByUUID) accounts() ([]domain.Account, error) {
 			return matches, err
 		}
 		a := domain.ReconstituteAccount(params)
-
-		pStatement, err := q.db.Prepare("SELECT AUTHOR_UUID, CREATED_AT, DELETED_AT, DRAFT, LIKE_COUNT, UPDATED_AT, UUID, TITLE, CONTENT FROM POST WHERE AUTHOR_UUID = ?;")
-		if err != nil {
+		if err = q.posts(&a); err != nil {
 			return matches, err
 		}
-		defer pStatement.Close()
+		matches = append(matches, a)
+	}
+	return matches, nil
+}
 
-		pRows, err := pStatement.Query(q.uuid.String())
-		if err != nil {
-			return matches, err
-		}
-		defer pRows.Close()
+// posts adds the posts written by the account to the provided account.
+func (q findAccountByUUID) posts(a *domain.Account) (err error) {
+	query := "SELECT AUTHOR_UUID, CREATED_AT, DELETED_AT, DRAFT, LIKE_COUNT, UPDATED_AT, UUID, TITLE, CONTENT FROM POST WHERE AUTHOR_UUID = ?;"
+	ctx, span := startStatement(q.ctx, q.tracer, query)
+	defer func() { EndSpan(span, err) }()
 
-		for pRows.Next() {
-			var params domain.PostParameters
-			err = pRows.Scan(
-				&params.AuthorUUID,
-				&params.CreatedAt,
-				&params.DeletedAt,
-				&params.Draft,
-				&params.Likes,
-				&params.UpdatedAt,
-				&params.UUID,
-				&params.Title,
-				&params.Content,
-			)
-			if err != nil {
-				return matches, err
-			}
-			a.AddPost(domain.ReconstitutePost(params))
-		}
+	pStatement, err := q.db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer pStatement.Close()
 
-		matches = append(matches, a)
+	pRows, err := pStatement.QueryContext(ctx, q.uuid.String())
+	if err != nil {
+		return err
 	}
-	return matches, nil
+	defer pRows.Close()
+
+	for pRows.Next() {
+		var params domain.PostParameters
+		err = pRows.Scan(
+			&params.AuthorUUID,
+			&params.CreatedAt,
+			&params.DeletedAt,
+			&params.Draft,
+			&params.Likes,
+			&params.UpdatedAt,
+			&params.UUID,
+			&params.Title,
+			&params.Content,
+		)
+		if err != nil {
+			return err
+		}
+		a.AddPost(domain.ReconstitutePost(params))
+	}
+	return nil
 }
@@ -0,0 +1,105 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/freerware/tutor/domain"
+	u "github.com/gofrs/uuid"
+)
+
+type findAccounts struct {
+	accountQuery
+
+	ctx    context.Context
+	offset int
+	limit  int
+}
+
+func NewFindAccountsQuery(ctx context.Context, db *sql.DB, offset, limit int) AccountPageQuery {
+	return &findAccounts{
+		accountQuery: accountQuery{
+			db: db,
+		},
+		ctx:    ctx,
+		offset: offset,
+		limit:  limit,
+	}
+}
+
+func (q *findAccounts) Execute() ([]domain.Account, int, error) {
+
+	// read within a read only transaction, so that the page and the total
+	// come from the same snapshot.
+	tx, err := q.db.BeginTx(q.ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	// count accounts.
+	var total int
+	if err = tx.QueryRowContext(q.ctx, "SELECT COUNT(*) FROM ACCOUNT").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// retrieve accounts.
+	accounts, err := q.accounts(tx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return accounts, total, tx.Commit()
+}
+
+func (q findAccounts) accounts(tx *sql.Tx) ([]domain.Account, error) {
+	matches := []domain.Account{}
+	statement, err := tx.PrepareContext(q.ctx, "SELECT CREATED_AT, DELETED_AT, GIVEN_NAME, PRIMARY_CREDENTIAL, SURNAME, UPDATED_AT, UUID FROM ACCOUNT ORDER BY CREATED_AT, UUID LIMIT ? OFFSET ?")
+	if err != nil {
+		return matches, err
+	}
+	defer statement.Close()
+
+	rows, err := statement.QueryContext(q.ctx, q.limit, q.offset)
+	if err != nil {
+		return matches, err
+	}
+	defer rows.Close()
+
+	params := []domain.AccountParameters{}
+	authors := []u.UUID{}
+	for rows.Next() {
+		var p domain.AccountParameters
+		err = rows.Scan(
+			&p.CreatedAt,
+			&p.DeletedAt,
+			&p.GivenName,
+			&p.Username,
+			&p.Surname,
+			&p.UpdatedAt,
+			&p.UUID,
+		)
+		if err != nil {
+			return matches, err
+		}
+		params = append(params, p)
+		authors = append(authors, p.UUID)
+	}
+	if err = rows.Err(); err != nil {
+		return matches, err
+	}
+
+	// retrieve the posts for the entire page at once.
+	posts, err := q.posts(tx, authors...)
+	if err != nil {
+		return matches, err
+	}
+
+	for _, p := range params {
+		p.Posts = posts[p.UUID]
+		matches = append(matches, domain.ReconstituteAccount(p))
+	}
+	return matches, nil
+}
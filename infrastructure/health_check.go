@@ -0,0 +1,157 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/freerware/tutor/config"
+	"go.uber.org/fx"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// metricsProbeTimeout bounds how long the metrics reporter is given to
+// refuse a probe.
+const metricsProbeTimeout = 100 * time.Millisecond
+
+// HealthCheck determines whether a component that the application depends
+// on is available.
+type HealthCheck struct {
+	Component string
+	Check     func(context.Context) error
+}
+
+// HealthCheckResult contributes a health check. Any module can provide one.
+type HealthCheckResult struct {
+	fx.Out
+
+	HealthCheck HealthCheck `group:"healthChecks"`
+}
+
+// NewDatabaseHealthCheck constructs a health check that pings the database.
+func NewDatabaseHealthCheck(parameters DBParameters) HealthCheckResult {
+	return HealthCheckResult{HealthCheck: HealthCheck{
+		Component: "database",
+		Check: func(ctx context.Context) error {
+			return parameters.DB.PingContext(ctx)
+		},
+	}}
+}
+
+// NewMigrationHealthCheck constructs a health check that ensures that the
+// database has been migrated to the latest migration shipped with the
+// application. Databases migrated beyond it pass, so that instances of the
+// previous release remain available while a release is rolled out.
+func NewMigrationHealthCheck(parameters DBParameters) (HealthCheckResult, error) {
+	expected, err := MigrationVersion()
+	if err != nil {
+		return HealthCheckResult{}, err
+	}
+	return HealthCheckResult{HealthCheck: HealthCheck{
+		Component: "migrations",
+		Check: func(ctx context.Context) error {
+			current, err := migratedVersion(ctx, parameters.DB)
+			if err != nil {
+				return err
+			}
+			if current < expected {
+				return fmt.Errorf(
+					"infrastructure: database is migrated to version %d, expected %d", current, expected)
+			}
+			return nil
+		},
+	}}, nil
+}
+
+// newStatsdHealthCheck constructs a health check that probes the statsd
+// server. Since metrics are reported over UDP, the server is only considered
+// unavailable when its address cannot be resolved, or when the probe is
+// refused.
+func newStatsdHealthCheck(c config.MetricsConfiguration) HealthCheck {
+	addr := fmt.Sprintf("%s:%d", c.Host, c.Port)
+	return HealthCheck{
+		Component: "metrics",
+		Check: func(ctx context.Context) error {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "udp", addr)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			// refusals of the probe surface on the subsequent read.
+			if _, err = conn.Write([]byte(c.Prefix + ".health:1|c")); err != nil {
+				return err
+			}
+			conn.SetReadDeadline(time.Now().Add(metricsProbeTimeout))
+			_, err = conn.Read(make([]byte, 1))
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil
+			}
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				return fmt.Errorf("infrastructure: metrics reporter at %s refused the probe", addr)
+			}
+			return err
+		},
+	}
+}
+
+// MigrationVersion provides the version of the latest migration shipped with
+// the application.
+func MigrationVersion() (int64, error) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("infrastructure: malformed migration %s: %w", entry.Name(), err)
+		}
+		latest = max(latest, version)
+	}
+	return latest, nil
+}
+
+// migratedVersion determines the version that goose has migrated the
+// database to. Versions that were rolled back are recorded again as not
+// applied, so the most recent record of each version is the one that counts.
+func migratedVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version_id, is_applied FROM goose_db_version ORDER BY id DESC")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	superseded := map[int64]bool{}
+	for rows.Next() {
+		var (
+			version int64
+			applied bool
+		)
+		if err := rows.Scan(&version, &applied); err != nil {
+			return 0, err
+		}
+		if superseded[version] {
+			continue
+		}
+		if applied {
+			return version, nil
+		}
+		superseded[version] = true
+	}
+	return 0, rows.Err()
+}
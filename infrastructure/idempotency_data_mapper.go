@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/freerware/work/v4/unit"
+	"go.uber.org/zap"
+)
+
+// IdempotencyDataMapper persists idempotency records within the same
+// transaction as the changes made by the requests they record.
+type IdempotencyDataMapper struct {
+	logger *zap.Logger
+}
+
+func NewIdempotencyDataMapper(logger *zap.Logger) IdempotencyDataMapper {
+	return IdempotencyDataMapper{logger: logger}
+}
+
+func (dm *IdempotencyDataMapper) Insert(ctx context.Context, mCtx unit.MapperContext, records ...any) error {
+	for _, r := range records {
+		record, ok := r.(IdempotencyRecord)
+		if !ok {
+			return ErrInvalidType
+		}
+		headers, err := json.Marshal(record.Header)
+		if err != nil {
+			return err
+		}
+
+		// an expired record of the same key may not have been purged yet, so
+		// it is replaced, freeing the key for reuse.
+		purge := "DELETE FROM IDEMPOTENCY_KEY WHERE IDEMPOTENCY_KEY = ? AND EXPIRES_AT <= ?"
+		if _, err = mCtx.Tx.ExecContext(ctx, purge, record.Key, record.CreatedAt); err != nil {
+			return failed(ctx, dm.logger, purge, err)
+		}
+
+		insert := "INSERT INTO IDEMPOTENCY_KEY (IDEMPOTENCY_KEY, FINGERPRINT, STATUS, HEADERS, BODY, CREATED_AT, EXPIRES_AT) VALUES (?, ?, ?, ?, ?, ?, ?)"
+		_, err = mCtx.Tx.ExecContext(ctx,
+			insert,
+			record.Key,
+			record.Fingerprint,
+			record.Status,
+			headers,
+			record.Body,
+			record.CreatedAt,
+			record.ExpiresAt,
+		)
+		if err != nil {
+			return failed(ctx, dm.logger, insert, err)
+		}
+	}
+	return nil
+}
+
+func (dm *IdempotencyDataMapper) Update(ctx context.Context, mCtx unit.MapperContext, records ...any) error {
+	for _, r := range records {
+		record, ok := r.(IdempotencyRecord)
+		if !ok {
+			return ErrInvalidType
+		}
+		headers, err := json.Marshal(record.Header)
+		if err != nil {
+			return err
+		}
+		update := "UPDATE IDEMPOTENCY_KEY SET FINGERPRINT = ?, STATUS = ?, HEADERS = ?, BODY = ?, CREATED_AT = ?, EXPIRES_AT = ? WHERE IDEMPOTENCY_KEY = ?"
+		_, err = mCtx.Tx.ExecContext(ctx,
+			update,
+			record.Fingerprint,
+			record.Status,
+			headers,
+			record.Body,
+			record.CreatedAt,
+			record.ExpiresAt,
+			record.Key,
+		)
+		if err != nil {
+			return failed(ctx, dm.logger, update, err)
+		}
+	}
+	return nil
+}
+
+func (dm *IdempotencyDataMapper) Delete(ctx context.Context, mCtx unit.MapperContext, records ...any) error {
+	for _, r := range records {
+		record, ok := r.(IdempotencyRecord)
+		if !ok {
+			return ErrInvalidType
+		}
+		remove := "DELETE FROM IDEMPOTENCY_KEY WHERE IDEMPOTENCY_KEY = ?"
+		if _, err := mCtx.Tx.ExecContext(ctx, remove, record.Key); err != nil {
+			return failed(ctx, dm.logger, remove, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// purgeInterval is how often expired idempotency records are purged.
+const purgeInterval = time.Hour
+
+type PurgeParameters struct {
+	fx.In
+
+	DB        *sql.DB `name:"rwDB"`
+	Lifecycle fx.Lifecycle
+	Logger    *zap.Logger
+}
+
+// PurgeIdempotencyRecords periodically purges the idempotency records that
+// have expired while the application runs, keeping the purge away from the
+// requests that record responses.
+func PurgeIdempotencyRecords(parameters PurgeParameters) {
+	stop := make(chan struct{})
+	parameters.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				ticker := time.NewTicker(purgeInterval)
+				defer ticker.Stop()
+				for {
+					purge(parameters.DB, parameters.Logger, time.Now())
+					select {
+					case <-ticker.C:
+					case <-stop:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			close(stop)
+			return nil
+		},
+	})
+}
+
+// purge deletes the idempotency records that expired by the provided time.
+func purge(db *sql.DB, logger *zap.Logger, now time.Time) {
+	ctx := context.Background()
+	statement := "DELETE FROM IDEMPOTENCY_KEY WHERE EXPIRES_AT <= ?"
+	result, err := db.ExecContext(ctx, statement, now)
+	if err != nil {
+		failed(ctx, logger, statement, err)
+		return
+	}
+	if purged, err := result.RowsAffected(); err == nil && purged > 0 {
+		logger.Info("purged expired idempotency records", zap.Int64("records", purged))
+	}
+}
@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyRecord records the response to a request made with an
+// idempotency key, so that retries of the request are answered with the
+// same response rather than repeating its changes. The fingerprint
+// identifies the request that the key was first used with.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Status      int
+	Header      map[string][]string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// IdempotencyRecordQuery is a query that retrieves at most one idempotency
+// record.
+type IdempotencyRecordQuery interface {
+	Execute() (*IdempotencyRecord, error)
+}
+
+type findIdempotencyRecord struct {
+	db  *sql.DB
+	key string
+	now time.Time
+}
+
+// NewFindIdempotencyRecordQuery constructs a query that retrieves the record
+// of the provided idempotency key, provided it has not expired by the
+// provided time.
+func NewFindIdempotencyRecordQuery(db *sql.DB, key string, now time.Time) IdempotencyRecordQuery {
+	return &findIdempotencyRecord{db: db, key: key, now: now}
+}
+
+func (q *findIdempotencyRecord) Execute() (*IdempotencyRecord, error) {
+	row := q.db.QueryRow("SELECT IDEMPOTENCY_KEY, FINGERPRINT, STATUS, HEADERS, BODY, CREATED_AT, EXPIRES_AT FROM IDEMPOTENCY_KEY WHERE IDEMPOTENCY_KEY = ? AND EXPIRES_AT > ?", q.key, q.now)
+	var (
+		record  IdempotencyRecord
+		headers []byte
+	)
+	err := row.Scan(
+		&record.Key,
+		&record.Fingerprint,
+		&record.Status,
+		&headers,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(headers, &record.Header); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type loggerKey struct{}
+
+// WithLogger provides a context that carries the provided logger, which
+// describes the request that the context belongs to.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// LoggerFrom provides the logger carried by the provided context, or the
+// fallback when it carries none.
+func LoggerFrom(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// failed logs the failure of the provided statement with the logger of the
+// request, so that it can be traced back to the request, and provides the
+// error.
+func failed(ctx context.Context, fallback *zap.Logger, statement string, err error) error {
+	LoggerFrom(ctx, fallback).Error("statement failed", zap.String("statement", statement), zap.Error(err))
+	return err
+}
@@ -0,0 +1,298 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cactus/go-statsd-client/statsd"
+	"github.com/freerware/tutor/config"
+	"github.com/uber-go/tally"
+	tstatsd "github.com/uber-go/tally/statsd"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// sampleInterval is how often the runtime and the database connection pool
+// are sampled.
+const sampleInterval = 10 * time.Second
+
+type ScopeParameters struct {
+	fx.In
+
+	Configuration config.Configuration
+	DB            *sql.DB `name:"rwDB"`
+	Lifecycle     fx.Lifecycle
+	Shutdowner    fx.Shutdowner
+	Logger        *zap.Logger
+}
+
+// ScopeResult provides the scope that metrics are reported with, along with
+// the health checks of its reporter.
+type ScopeResult struct {
+	fx.Out
+
+	Scope        tally.Scope
+	HealthChecks []HealthCheck `group:"healthChecks,flatten"`
+}
+
+// NewScope constructs the scope that metrics are reported with, using the
+// reporter that the configuration selects. The scope also reports the state
+// of the runtime and of the database connection pool, sampled periodically
+// while the application runs.
+func NewScope(parameters ScopeParameters) (ScopeResult, error) {
+	c := parameters.Configuration.Metrics
+	options := tally.ScopeOptions{Tags: map[string]string{}}
+	var checks []HealthCheck
+	switch c.Reporter {
+	case "", "statsd":
+		addr := fmt.Sprintf("%s:%d", c.Host, c.Port)
+		flushInterval := time.Duration(c.MaxFlushInterval) * time.Millisecond
+		statter, err :=
+			statsd.NewBufferedClient(addr, c.Prefix, flushInterval, c.MaxFlushBytes)
+		if err != nil {
+			return ScopeResult{}, err
+		}
+		reporter := tstatsd.NewReporter(statter, tstatsd.Options{
+			SampleRate: 1.0,
+		})
+		options.Reporter = flattenTags(reporter)
+		checks = append(checks, newStatsdHealthCheck(c))
+	case "prometheus":
+		reporter, err := newPrometheusReporter()
+		if err != nil {
+			return ScopeResult{}, err
+		}
+		options.Reporter = reporter
+		options.Prefix = c.Prefix
+		options.Separator = "_"
+		options.SanitizeOptions = &prometheusSanitizeOptions
+		exposition := newExposition(c.Prometheus, reporter.HTTPHandler())
+		parameters.Lifecycle.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				return exposition.start(parameters.Shutdowner, parameters.Logger)
+			},
+			OnStop: exposition.server.Shutdown,
+		})
+		checks = append(checks, exposition.healthCheck())
+	case "none":
+		options.Reporter = tally.NullStatsReporter
+	default:
+		return ScopeResult{}, fmt.Errorf("infrastructure: unsupported metrics reporter %q", c.Reporter)
+	}
+	scope, closer := tally.NewRootScope(options, time.Second)
+
+	// sample the runtime and the connection pool until the application stops.
+	stop := make(chan struct{})
+	parameters.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				ticker := time.NewTicker(sampleInterval)
+				defer ticker.Stop()
+				for {
+					sample(scope, parameters.DB)
+					select {
+					case <-ticker.C:
+					case <-stop:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			close(stop)
+			return closer.Close()
+		},
+	})
+	return ScopeResult{Scope: scope, HealthChecks: checks}, nil
+}
+
+// sample reports the state of the runtime and of the provided database
+// connection pool.
+func sample(scope tally.Scope, db *sql.DB) {
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	rs := scope.SubScope("runtime")
+	rs.Gauge("goroutines").Update(float64(runtime.NumGoroutine()))
+	rs.Gauge("heap_alloc_bytes").Update(float64(memory.HeapAlloc))
+	rs.Gauge("heap_objects").Update(float64(memory.HeapObjects))
+	rs.Gauge("sys_bytes").Update(float64(memory.Sys))
+	rs.Gauge("gc_cycles").Update(float64(memory.NumGC))
+	rs.Gauge("gc_pause_seconds").Update(time.Duration(memory.PauseTotalNs).Seconds())
+
+	stats := db.Stats()
+	ds := scope.SubScope("db")
+	ds.Gauge("max_open_connections").Update(float64(stats.MaxOpenConnections))
+	ds.Gauge("open_connections").Update(float64(stats.OpenConnections))
+	ds.Gauge("in_use_connections").Update(float64(stats.InUse))
+	ds.Gauge("idle_connections").Update(float64(stats.Idle))
+	ds.Gauge("wait_count").Update(float64(stats.WaitCount))
+	ds.Gauge("wait_seconds").Update(stats.WaitDuration.Seconds())
+	ds.Gauge("max_idle_closed").Update(float64(stats.MaxIdleClosed))
+	ds.Gauge("max_lifetime_closed").Update(float64(stats.MaxLifetimeClosed))
+}
+
+// exposition serves metrics at /metrics to be scraped by Prometheus.
+type exposition struct {
+	server *http.Server
+	addr   string
+}
+
+func newExposition(c config.PrometheusConfiguration, handler http.Handler) *exposition {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	return &exposition{server: &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", c.Host, c.Port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}}
+}
+
+// start binds the address of the exposition and serves it in the background,
+// shutting the application down if serving fails.
+func (e *exposition) start(shutdowner fx.Shutdowner, logger *zap.Logger) error {
+	listener, err := net.Listen("tcp", e.server.Addr)
+	if err != nil {
+		return err
+	}
+	e.addr = listener.Addr().String()
+	go func() {
+		if err := e.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("failed to serve metrics", zap.Error(err))
+			shutdowner.Shutdown(fx.ExitCode(1))
+		}
+	}()
+	return nil
+}
+
+// healthCheck constructs a health check that scrapes the exposition.
+func (e *exposition) healthCheck() HealthCheck {
+	return HealthCheck{
+		Component: "metrics",
+		Check: func(ctx context.Context) error {
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+e.addr+"/metrics", nil)
+			if err != nil {
+				return err
+			}
+			response, err := http.DefaultClient.Do(request)
+			if err != nil {
+				return err
+			}
+			defer response.Body.Close()
+			if response.StatusCode != http.StatusOK {
+				return fmt.Errorf("infrastructure: metrics exposition responded with %s", response.Status)
+			}
+			return nil
+		},
+	}
+}
+
+// flattenedReporter reports the tags of each metric as part of its name, in
+// order of tag name, for reporters such as statsd that cannot report tags.
+// A counter named requests and tagged with a route of /accounts is reported
+// as requests.route.accounts.
+type flattenedReporter struct {
+	reporter tally.StatsReporter
+}
+
+// flattenTags wraps the provided reporter so that it reports tags as part of
+// metric names.
+func flattenTags(reporter tally.StatsReporter) tally.StatsReporter {
+	return &flattenedReporter{reporter: reporter}
+}
+
+func (r *flattenedReporter) ReportCounter(name string, tags map[string]string, value int64) {
+	r.reporter.ReportCounter(flatten(name, tags), nil, value)
+}
+
+func (r *flattenedReporter) ReportGauge(name string, tags map[string]string, value float64) {
+	r.reporter.ReportGauge(flatten(name, tags), nil, value)
+}
+
+func (r *flattenedReporter) ReportTimer(name string, tags map[string]string, interval time.Duration) {
+	r.reporter.ReportTimer(flatten(name, tags), nil, interval)
+}
+
+func (r *flattenedReporter) ReportHistogramValueSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound float64,
+	samples int64,
+) {
+	r.reporter.ReportHistogramValueSamples(
+		flatten(name, tags), nil, buckets, bucketLowerBound, bucketUpperBound, samples)
+}
+
+func (r *flattenedReporter) ReportHistogramDurationSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound time.Duration,
+	samples int64,
+) {
+	r.reporter.ReportHistogramDurationSamples(
+		flatten(name, tags), nil, buckets, bucketLowerBound, bucketUpperBound, samples)
+}
+
+func (r *flattenedReporter) Capabilities() tally.Capabilities { return r }
+
+func (r *flattenedReporter) Reporting() bool { return r.reporter.Capabilities().Reporting() }
+
+func (r *flattenedReporter) Tagging() bool { return true }
+
+func (r *flattenedReporter) Flush() { r.reporter.Flush() }
+
+// flatten appends the provided tags to the provided metric name. Tags are
+// reduced to letters, digits and hyphens separated by single underscores,
+// so that they cannot introduce path segments or wildcards of their own.
+func flatten(name string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, key := range keys {
+		b.WriteString(".")
+		b.WriteString(sanitize(key))
+		b.WriteString(".")
+		b.WriteString(sanitize(tags[key]))
+	}
+	return b.String()
+}
+
+func sanitize(s string) string {
+	var b strings.Builder
+	separated := true
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+			separated = false
+		case !separated:
+			b.WriteRune('_')
+			separated = true
+		}
+	}
+	sanitized := strings.TrimSuffix(b.String(), "_")
+	if sanitized == "" {
+		return "none"
+	}
+	return sanitized
+}
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `IDEMPOTENCY_KEY` (
+  `IDEMPOTENCY_KEY`   VARCHAR(255)    NOT NULL,
+  `FINGERPRINT`       CHAR(64)        NOT NULL,
+  `STATUS`            INT             NOT NULL,
+  `HEADERS`           TEXT            NOT NULL,
+  `BODY`              MEDIUMBLOB      NOT NULL,
+  `CREATED_AT`        DATETIME        NOT NULL,
+  `EXPIRES_AT`        DATETIME        NOT NULL,
+
+  PRIMARY KEY (`IDEMPOTENCY_KEY`),
+  INDEX (`EXPIRES_AT`)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `IDEMPOTENCY_KEY`;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `CREDENTIAL` (
+  `ACCOUNT_UUID`    VARCHAR(36)     NOT NULL,
+  `PASSWORD_HASH`   VARCHAR(255)    NOT NULL,
+  `CREATED_AT`      DATETIME        NOT NULL,
+  `UPDATED_AT`      DATETIME        NOT NULL,
+
+  PRIMARY KEY (`ACCOUNT_UUID`)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+-- accounts without a username are exempt, since only one of them could
+-- otherwise exist.
+CREATE UNIQUE INDEX `ACCOUNT_PRIMARY_CREDENTIAL` ON `ACCOUNT` ((NULLIF(`PRIMARY_CREDENTIAL`, '')));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX `ACCOUNT_PRIMARY_CREDENTIAL` ON `ACCOUNT`;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE `CREDENTIAL`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `CREDENTIAL` ADD COLUMN `ROLE` VARCHAR(32) NOT NULL DEFAULT 'member' AFTER `PASSWORD_HASH`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `CREDENTIAL` DROP COLUMN `ROLE`;
+-- +goose StatementEnd
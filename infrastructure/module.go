@@ -6,14 +6,12 @@ import (
 	"time"
 
 	"github.com/avast/retry-go"
-	"github.com/cactus/go-statsd-client/statsd"
 	"github.com/freerware/tutor/config"
 	"github.com/freerware/tutor/domain"
 	"github.com/freerware/work/v4/unit"
-	"github.com/freerware/workfx/v4"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/uber-go/tally"
-	tstatsd "github.com/uber-go/tally/statsd"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -38,6 +36,12 @@ type DBParameters struct {
 
 var Module = fx.Options(
 	fx.Provide(NewQueryer),
+	fx.Provide(NewUniter),
+	fx.Provide(NewTracerProvider),
+	fx.Provide(NewDatabaseHealthCheck),
+	fx.Provide(NewMigrationHealthCheck),
+	fx.Provide(NewScope),
+	fx.Invoke(PurgeIdempotencyRecords),
 	fx.Provide(func(c config.Configuration) (DBResult, error) {
 		var db *sql.DB
 		connect := func() (err error) {
@@ -63,29 +67,15 @@ var Module = fx.Options(
 		}
 		return DBResult{DB: db}, nil
 	}),
-	fx.Provide(func(c config.Configuration) (tally.Scope, error) {
-		addr := fmt.Sprintf("%s:%d", c.Metrics.Host, c.Metrics.Port)
-		flushInterval := time.Duration(c.Metrics.MaxFlushInterval) * time.Millisecond
-		statter, err :=
-			statsd.NewBufferedClient(
-				addr, c.Metrics.Prefix, flushInterval, c.Metrics.MaxFlushBytes)
-		if err != nil {
-			return nil, err
-		}
-		reporter := tstatsd.NewReporter(statter, tstatsd.Options{
-			SampleRate: 1.0,
-		})
-		scope, _ := tally.NewRootScope(tally.ScopeOptions{
-			Tags:     map[string]string{},
-			Reporter: reporter,
-		}, time.Second)
-		return scope, nil
-	}),
-	fx.Provide(func(l *zap.Logger) UnitResult {
+	fx.Provide(func(l *zap.Logger, tp trace.TracerProvider) UnitResult {
 		dataMappers := make(map[unit.TypeName]unit.DataMapper)
 		accountTN := unit.TypeNameOf(domain.Account{})
-		dm := NewAccountDataMapper(AccountDataMapperParameters{Logger: l})
+		dm := NewAccountDataMapper(AccountDataMapperParameters{Logger: l, TracerProvider: tp})
 		dataMappers[accountTN] = &dm
+		idm := NewIdempotencyDataMapper(l)
+		dataMappers[unit.TypeNameOf(IdempotencyRecord{})] = &idm
+		cdm := NewCredentialDataMapper(l)
+		dataMappers[unit.TypeNameOf(Credential{})] = &cdm
 		return UnitResult{Option: unit.DataMappers(dataMappers)}
 	}),
 	fx.Provide(func(l *zap.Logger) UnitResult {
@@ -97,5 +87,4 @@ var Module = fx.Options(
 	fx.Provide(func(parameters DBParameters) UnitResult {
 		return UnitResult{Option: unit.DB(parameters.DB)}
 	}),
-	workfx.Module,
 )
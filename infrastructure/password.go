@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// The parameters that passwords are hashed with, as recommended by OWASP
+// for Argon2id.
+const (
+	argon2Memory      = 19 * 1024
+	argon2Iterations  = 2
+	argon2Parallelism = 1
+	argon2SaltLength  = 16
+	argon2KeyLength   = 32
+)
+
+// ErrMalformedPasswordHash indicates that a stored password hash cannot be
+// interpreted.
+var ErrMalformedPasswordHash = errors.New("infrastructure: malformed password hash")
+
+// HashPassword hashes the provided password with Argon2id and a random salt,
+// encoding the hash along with its parameters in the PHC string format.
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2Iterations, argon2Memory, argon2Parallelism, argon2KeyLength)
+	encoding := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argon2Memory, argon2Iterations, argon2Parallelism,
+		encoding.EncodeToString(salt), encoding.EncodeToString(key)), nil
+}
+
+// VerifyPassword indicates if the provided password is the one that the
+// provided hash was computed from, using the parameters encoded within the
+// hash so that hashes outlive changes to the parameters.
+func VerifyPassword(hash, password string) (bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, ErrMalformedPasswordHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrMalformedPasswordHash
+	}
+	var (
+		memory      uint32
+		iterations  uint32
+		parallelism uint8
+	)
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &parallelism); err != nil {
+		return false, ErrMalformedPasswordHash
+	}
+	encoding := base64.RawStdEncoding
+	salt, err := encoding.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrMalformedPasswordHash
+	}
+	expected, err := encoding.DecodeString(parts[5])
+	if err != nil || len(expected) == 0 {
+		return false, ErrMalformedPasswordHash
+	}
+	key := argon2.IDKey([]byte(password), salt, iterations, memory, parallelism, uint32(len(expected)))
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	u "github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PostAuthorQuery is a query that retrieves the UUID of the account that
+// wrote a post, if the post exists.
+type PostAuthorQuery interface {
+	Execute() (*u.UUID, error)
+}
+
+type findPostAuthor struct {
+	db       *sql.DB
+	ctx      context.Context
+	tracer   trace.Tracer
+	postUUID u.UUID
+}
+
+// NewFindPostAuthorQuery constructs a query that retrieves the author of the
+// post with the provided UUID, whichever account that is.
+func NewFindPostAuthorQuery(
+	ctx context.Context, db *sql.DB, tracer trace.Tracer, postUUID u.UUID) PostAuthorQuery {
+	return &findPostAuthor{db: db, ctx: ctx, tracer: tracer, postUUID: postUUID}
+}
+
+func (q *findPostAuthor) Execute() (_ *u.UUID, err error) {
+	query := "SELECT AUTHOR_UUID FROM POST WHERE UUID = ?"
+	ctx, span := startStatement(q.ctx, q.tracer, query)
+	defer func() { EndSpan(span, err) }()
+
+	var author u.UUID
+	err = q.db.QueryRowContext(ctx, query, q.postUUID.String()).Scan(&author)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &author, nil
+}
@@ -0,0 +1,245 @@
+package infrastructure
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/uber-go/tally"
+)
+
+// prometheusSanitizeOptions reduce metric names and tag names to the
+// characters that Prometheus accepts. Tag values are left as they are.
+var prometheusSanitizeOptions = tally.SanitizeOptions{
+	NameCharacters: tally.ValidCharacters{
+		Ranges:     tally.AlphanumericRange,
+		Characters: tally.UnderscoreCharacters,
+	},
+	KeyCharacters: tally.ValidCharacters{
+		Ranges:     tally.AlphanumericRange,
+		Characters: tally.UnderscoreCharacters,
+	},
+	ValueCharacters: tally.ValidCharacters{
+		Ranges: []tally.SanitizeRange{{0, unicode.MaxRune}},
+	},
+	ReplacementCharacter: tally.DefaultReplacementCharacter,
+}
+
+// prometheusReporter accumulates the metrics that tally reports so that they
+// can be scraped by Prometheus. Counters are summed, gauges keep their latest
+// value, and timers and histograms are collected as histograms in seconds.
+// Since tally reports the samples of a histogram by bucket, its sum is
+// estimated from the midpoints of the buckets.
+type prometheusReporter struct {
+	registry *prometheus.Registry
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// newPrometheusReporter constructs a reporter, along with the registry that
+// it is collected by.
+func newPrometheusReporter() (*prometheusReporter, error) {
+	r := &prometheusReporter{
+		registry: prometheus.NewRegistry(),
+		series:   map[string]*series{},
+	}
+	if err := r.registry.Register(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// HTTPHandler serves the metrics in the Prometheus exposition format.
+func (r *prometheusReporter) HTTPHandler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// series is a metric with a particular set of tags.
+type series struct {
+	valueType   prometheus.ValueType
+	histogram   bool
+	name        string
+	labelNames  []string
+	labelValues []string
+
+	value   float64
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+func (r *prometheusReporter) get(name string, tags map[string]string) *series {
+	names := make([]string, 0, len(tags))
+	for key := range tags {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	for i, key := range names {
+		values[i] = tags[key]
+	}
+	id := name + "\xff" + strings.Join(names, "\xff") + "\xff" + strings.Join(values, "\xff")
+	s, ok := r.series[id]
+	if !ok {
+		s = &series{name: name, labelNames: names, labelValues: values}
+		r.series[id] = s
+	}
+	return s
+}
+
+func (r *prometheusReporter) ReportCounter(name string, tags map[string]string, value int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name, tags)
+	s.valueType = prometheus.CounterValue
+	s.value += float64(value)
+}
+
+func (r *prometheusReporter) ReportGauge(name string, tags map[string]string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name, tags)
+	s.valueType = prometheus.GaugeValue
+	s.value = value
+}
+
+func (r *prometheusReporter) ReportTimer(name string, tags map[string]string, interval time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name, tags)
+	seconds := interval.Seconds()
+	upper := math.Inf(1)
+	for _, bound := range prometheus.DefBuckets {
+		if seconds <= bound {
+			upper = bound
+			break
+		}
+	}
+	s.observe(upper, seconds, 1)
+}
+
+func (r *prometheusReporter) ReportHistogramValueSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound float64,
+	samples int64,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lower, upper := bucketLowerBound, bucketUpperBound
+	if lower == -math.MaxFloat64 {
+		lower = math.Inf(-1)
+	}
+	if upper == math.MaxFloat64 {
+		upper = math.Inf(1)
+	}
+	r.get(name, tags).observe(upper, midpoint(lower, upper)*float64(samples), samples)
+}
+
+func (r *prometheusReporter) ReportHistogramDurationSamples(
+	name string,
+	tags map[string]string,
+	buckets tally.Buckets,
+	bucketLowerBound,
+	bucketUpperBound time.Duration,
+	samples int64,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lower, upper := bucketLowerBound.Seconds(), bucketUpperBound.Seconds()
+	if bucketLowerBound == math.MinInt64 {
+		lower = math.Inf(-1)
+	}
+	if bucketUpperBound == math.MaxInt64 {
+		upper = math.Inf(1)
+	}
+	r.get(name, tags).observe(upper, midpoint(lower, upper)*float64(samples), samples)
+}
+
+func (r *prometheusReporter) Capabilities() tally.Capabilities { return r }
+
+func (r *prometheusReporter) Reporting() bool { return true }
+
+func (r *prometheusReporter) Tagging() bool { return true }
+
+func (r *prometheusReporter) Flush() {}
+
+// Describe describes nothing, so that the reporter is collected without
+// the metrics it reports having to be known in advance.
+func (r *prometheusReporter) Describe(chan<- *prometheus.Desc) {}
+
+func (r *prometheusReporter) Collect(metrics chan<- prometheus.Metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.series {
+		desc := prometheus.NewDesc(s.name, s.name, s.labelNames, nil)
+		var (
+			metric prometheus.Metric
+			err    error
+		)
+		if s.histogram {
+			metric, err = prometheus.NewConstHistogram(
+				desc, s.count, s.sum, s.cumulativeBuckets(), s.labelValues...)
+		} else {
+			metric, err = prometheus.NewConstMetric(desc, s.valueType, s.value, s.labelValues...)
+		}
+		if err != nil {
+			metric = prometheus.NewInvalidMetric(desc, err)
+		}
+		metrics <- metric
+	}
+}
+
+// observe records samples within the bucket bounded by the provided upper
+// bound, adding the provided sum of their values.
+func (s *series) observe(upper, sum float64, samples int64) {
+	if s.buckets == nil {
+		s.buckets = map[float64]uint64{}
+	}
+	s.histogram = true
+	s.count += uint64(samples)
+	s.sum += sum
+	if !math.IsInf(upper, 1) {
+		s.buckets[upper] += uint64(samples)
+	}
+}
+
+// cumulativeBuckets provides the number of samples at or below each upper
+// bound, as Prometheus expects.
+func (s *series) cumulativeBuckets() map[float64]uint64 {
+	bounds := make([]float64, 0, len(s.buckets))
+	for bound := range s.buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+	cumulative := make(map[float64]uint64, len(bounds))
+	var total uint64
+	for _, bound := range bounds {
+		total += s.buckets[bound]
+		cumulative[bound] = total
+	}
+	return cumulative
+}
+
+// midpoint estimates the value of the samples within a bucket, using its
+// finite bound when the other is not.
+func midpoint(lower, upper float64) float64 {
+	switch {
+	case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+		return 0
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	}
+	return (lower + upper) / 2
+}
@@ -1,32 +1,68 @@
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	u "github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 )
 
 type Queryer interface {
-	Query(u.UUID) AccountQuery
+	Query(context.Context, u.UUID) AccountQuery
+	QueryPage(ctx context.Context, offset, limit int) AccountPageQuery
+	QueryCursor(filter AccountFilter, posts bool) AccountCursorQuery
+	QueryIdempotencyRecord(key string, now time.Time) IdempotencyRecordQuery
+	QueryCredential(ctx context.Context, accountUUID u.UUID) CredentialQuery
+	QueryCredentials(ctx context.Context, username string) CredentialQuery
+	QueryPostAuthor(ctx context.Context, postUUID u.UUID) PostAuthorQuery
 }
 
 type queryer struct {
-	db *sql.DB
+	db     *sql.DB
+	tracer trace.Tracer
 }
 
 type QueryerParameters struct {
 	fx.In
 
-	DB *sql.DB `name:"rwDB"`
+	DB             *sql.DB `name:"rwDB"`
+	TracerProvider trace.TracerProvider
 }
 
 func NewQueryer(parameters QueryerParameters) Queryer {
 	return &queryer{
-		db: parameters.DB,
+		db:     parameters.DB,
+		tracer: parameters.TracerProvider.Tracer(instrumentationName),
 	}
 }
 
-func (f *queryer) Query(uuid u.UUID) AccountQuery {
-	return NewFindAccountByUUIDQuery(f.db, uuid)
+func (f *queryer) Query(ctx context.Context, uuid u.UUID) AccountQuery {
+	return NewFindAccountByUUIDQuery(ctx, f.db, f.tracer, uuid)
+}
+
+func (f *queryer) QueryPage(ctx context.Context, offset, limit int) AccountPageQuery {
+	return NewFindAccountsQuery(ctx, f.db, offset, limit)
+}
+
+func (f *queryer) QueryCursor(filter AccountFilter, posts bool) AccountCursorQuery {
+	return NewFindAccountsCursorQuery(f.db, filter, posts)
+}
+
+func (f *queryer) QueryIdempotencyRecord(key string, now time.Time) IdempotencyRecordQuery {
+	return NewFindIdempotencyRecordQuery(f.db, key, now)
+}
+
+func (f *queryer) QueryCredential(ctx context.Context, accountUUID u.UUID) CredentialQuery {
+	return NewFindCredentialQuery(ctx, f.db, f.tracer, accountUUID)
+}
+
+func (f *queryer) QueryCredentials(ctx context.Context, username string) CredentialQuery {
+	return NewFindCredentialsByUsernameQuery(ctx, f.db, f.tracer, username)
+}
+
+func (f *queryer) QueryPostAuthor(ctx context.Context, postUUID u.UUID) PostAuthorQuery {
+	return NewFindPostAuthorQuery(ctx, f.db, f.tracer, postUUID)
 }
@@ -0,0 +1,102 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/freerware/tutor/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+)
+
+// instrumentationName identifies the spans started by the infrastructure.
+const instrumentationName = "github.com/freerware/tutor/infrastructure"
+
+// NewTracerProvider constructs the tracer provider that exports spans as
+// configured, and registers it globally along with the W3C trace context
+// propagator. The spans that remain buffered are exported as the
+// application stops.
+func NewTracerProvider(lc fx.Lifecycle, c config.Configuration) (trace.TracerProvider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch c.Tracing.Exporter {
+	case "", "none":
+		return noop.NewTracerProvider(), nil
+	case "otlp":
+		var options []otlptracehttp.Option
+		if c.Tracing.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(c.Tracing.Endpoint))
+		}
+		if c.Tracing.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		var err error
+		if exporter, err = otlptracehttp.New(context.Background(), options...); err != nil {
+			return nil, err
+		}
+	case "stdout":
+		var options []stdouttrace.Option
+		if c.Tracing.File != "" {
+			f, err := os.OpenFile(c.Tracing.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, err
+			}
+			lc.Append(fx.Hook{OnStop: func(context.Context) error { return f.Close() }})
+			options = append(options, stdouttrace.WithWriter(f))
+		}
+		var err error
+		if exporter, err = stdouttrace.New(options...); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("infrastructure: unknown span exporter %q", c.Tracing.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL, semconv.ServiceName("tutor"))),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(c.Tracing.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	lc.Append(fx.Hook{OnStop: provider.Shutdown})
+	return provider, nil
+}
+
+// EndSpan ends the provided span, recording the provided error, if any, as
+// the reason the operation it describes failed.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startStatement starts a span describing the execution of the provided SQL
+// statement.
+func startStatement(
+	ctx context.Context, tracer trace.Tracer, statement string) (context.Context, trace.Span) {
+	operation, _, _ := strings.Cut(strings.TrimSpace(statement), " ")
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			semconv.DBOperation(operation),
+			semconv.DBStatement(statement),
+		))
+}
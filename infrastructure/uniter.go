@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/freerware/work/v4/unit"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type UniterParameters struct {
+	fx.In
+
+	Options        []unit.Option `group:"unitOptions"`
+	TracerProvider trace.TracerProvider
+}
+
+// Uniter constructs units of work that log with the logger of the request
+// they are constructed for, and that trace each save.
+type Uniter struct {
+	options []unit.Option
+	tracer  trace.Tracer
+}
+
+func NewUniter(parameters UniterParameters) Uniter {
+	return Uniter{
+		options: parameters.Options,
+		tracer:  parameters.TracerProvider.Tracer(instrumentationName),
+	}
+}
+
+// Unit constructs a new unit of work for the request that the provided
+// context belongs to.
+func (u Uniter) Unit(ctx context.Context) (unit.Unit, error) {
+	options := append([]unit.Option{}, u.options...)
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		options = append(options, unit.Logger(logger))
+	}
+	un, err := unit.New(options...)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedUnit{Unit: un, tracer: u.tracer}, nil
+}
+
+// tracedUnit is a unit of work that describes each save with a span.
+type tracedUnit struct {
+	unit.Unit
+
+	tracer trace.Tracer
+}
+
+func (u *tracedUnit) Save(ctx context.Context) (err error) {
+	ctx, span := u.tracer.Start(ctx, "unit.Save")
+	defer func() { EndSpan(span, err) }()
+	return u.Unit.Save(ctx)
+}